@@ -0,0 +1,186 @@
+package botgolang
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultAckMaxAttempts = 5
+
+// ackTracker keeps delivered events until they are acked.
+// The offset is committed up to the last event of the acked prefix in the delivery order.
+type ackTracker struct {
+	mu          sync.Mutex
+	timeout     time.Duration
+	maxAttempts int
+	pending     []*pendingEvent
+}
+
+type pendingEvent struct {
+	event       Event
+	attempt     int
+	deliveredAt time.Time
+	acked       bool
+}
+
+func newAckTracker(timeout time.Duration, maxAttempts int) *ackTracker {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultAckMaxAttempts
+	}
+	return &ackTracker{timeout: timeout, maxAttempts: maxAttempts}
+}
+
+// add registers the delivered event and returns its delivery attempt.
+// The event received again while it is pending is counted as the next attempt.
+func (t *ackTracker) add(event Event, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, p := range t.pending {
+		if p.event.EventID == event.EventID && !p.acked {
+			p.attempt++
+			p.deliveredAt = now
+			return p.attempt
+		}
+	}
+
+	t.pending = append(t.pending, &pendingEvent{event: event, attempt: 1, deliveredAt: now})
+	return 1
+}
+
+// ack marks the event processed and returns the id of the event to commit,
+// ok is false if the acked prefix has not grown
+func (t *ackTracker) ack(eventID int) (commit int, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, p := range t.pending {
+		if p.event.EventID == eventID {
+			p.acked = true
+			break
+		}
+	}
+
+	for len(t.pending) > 0 && t.pending[0].acked {
+		commit, ok = t.pending[0].event.EventID, true
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+	}
+
+	return commit, ok
+}
+
+// expired returns unacked events delivered longer than timeout ago and marks them delivered again.
+// Events which used all attempts are returned as exhausted instead, they are not delivered again.
+func (t *ackTracker) expired(now time.Time) (expired, exhausted []*pendingEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, p := range t.pending {
+		if p.acked || now.Sub(p.deliveredAt) < t.timeout {
+			continue
+		}
+
+		if p.attempt >= t.maxAttempts {
+			exhausted = append(exhausted, &pendingEvent{event: p.event, attempt: p.attempt, deliveredAt: p.deliveredAt})
+			continue
+		}
+
+		p.attempt++
+		p.deliveredAt = now
+		expired = append(expired, &pendingEvent{event: p.event, attempt: p.attempt, deliveredAt: now})
+	}
+
+	return expired, exhausted
+}
+
+// reset forgets the pending events and returns the id of the first unacked one, 0 if there is none
+func (t *ackTracker) reset() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	first := 0
+	for _, p := range t.pending {
+		if !p.acked {
+			first = p.event.EventID
+			break
+		}
+	}
+
+	t.pending = nil
+	return first
+}
+
+// len returns the number of unacked events
+func (t *ackTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.pending)
+}
+
+// ackEvent commits the offset if the event completes the acked prefix
+func (u *Updater) ackEvent(eventID int) {
+	commit, ok := u.acks.ack(eventID)
+	if !ok {
+		return
+	}
+
+	u.saveOffset(context.Background(), commit)
+}
+
+// redeliver sends unacked events again after the ack timeout until ctx is done
+func (u *Updater) redeliver(ctx context.Context, ch chan<- Event) {
+	interval := u.acks.timeout / 2
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, exhausted := u.acks.expired(now)
+			for _, p := range exhausted {
+				u.giveUp(p)
+			}
+
+			for _, p := range expired {
+				event := p.event
+				event.attempt = p.attempt
+
+				u.logger.Warn("redelivering unacked event", LogFields{
+					"eventId": event.EventID,
+					"attempt": event.attempt,
+				})
+
+				if !u.send(ctx, ch, &event) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// giveUp stops redelivery of the event which was not acked in all attempts,
+// so it does not hold back the offset
+func (u *Updater) giveUp(p *pendingEvent) {
+	event := p.event
+	event.attempt = p.attempt
+
+	if u.onGiveUp != nil {
+		u.onGiveUp(event)
+	} else {
+		u.logger.Error("giving up unacked event", LogFields{
+			"eventId":  event.EventID,
+			"attempts": event.attempt,
+		})
+	}
+
+	u.ackEvent(event.EventID)
+}
@@ -0,0 +1,244 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	tracker := newAckTracker(time.Second, 0)
+	for _, eventID := range []int{3, 5, 8} {
+		tracker.add(Event{EventID: eventID}, now)
+	}
+
+	_, ok := tracker.ack(5)
+	assert.False(ok)
+
+	commit, ok := tracker.ack(3)
+	assert.True(ok)
+	assert.Equal(5, commit)
+	assert.Equal(1, tracker.len())
+
+	expired, _ := tracker.expired(now.Add(time.Millisecond))
+	assert.Empty(expired)
+	expired, exhausted := tracker.expired(now.Add(time.Second))
+	if assert.Len(expired, 1) {
+		assert.Equal(8, expired[0].event.EventID)
+		assert.Equal(2, expired[0].attempt)
+	}
+	assert.Empty(exhausted)
+	expired, _ = tracker.expired(now.Add(time.Second))
+	assert.Empty(expired)
+
+	commit, ok = tracker.ack(8)
+	assert.True(ok)
+	assert.Equal(8, commit)
+
+	_, ok = tracker.ack(8)
+	assert.False(ok)
+}
+
+func TestAckTracker_Attempts(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	tracker := newAckTracker(time.Second, 2)
+	assert.Equal(1, tracker.add(Event{EventID: 3}, now))
+	assert.Equal(1, tracker.add(Event{EventID: 5}, now))
+	assert.Equal(2, tracker.add(Event{EventID: 3}, now))
+	assert.Equal(2, tracker.len())
+
+	expired, exhausted := tracker.expired(now.Add(time.Second))
+	if assert.Len(exhausted, 1) {
+		assert.Equal(3, exhausted[0].event.EventID)
+		assert.Equal(2, exhausted[0].attempt)
+	}
+	if assert.Len(expired, 1) {
+		assert.Equal(5, expired[0].event.EventID)
+	}
+
+	assert.Equal(3, tracker.reset())
+	assert.Equal(0, tracker.len())
+	assert.Equal(0, tracker.reset())
+}
+
+func TestUpdater_Ack(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("lastEventId") != "0" {
+			<-r.Context().Done()
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	store := &MemoryOffsetStore{}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.offsets = store
+	updater.acks = newAckTracker(50*time.Millisecond, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan Event)
+	go updater.RunUpdatesCheck(ctx, ch)
+
+	offset := func() int {
+		eventID, _ := store.Load(context.Background())
+		return eventID
+	}
+
+	var unacked Event
+	for i := 0; i < 8; i++ {
+		event := <-ch
+		assert.Equal(1, event.DeliveryAttempt())
+		if event.EventID == 3 {
+			unacked = event
+			continue
+		}
+		event.Ack()
+	}
+	assert.Equal(2, offset())
+
+	redelivered := <-ch
+	require.Equal(3, redelivered.EventID)
+	assert.Equal(2, redelivered.DeliveryAttempt())
+	assert.True(redelivered.Redelivered())
+	assert.False(unacked.Redelivered())
+
+	redelivered.Ack()
+	assert.Equal(8, offset())
+	assert.Equal(0, updater.acks.len())
+}
+
+func TestUpdater_AckGiveUp(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("lastEventId") != "0" {
+			<-r.Context().Done()
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	store := &MemoryOffsetStore{}
+	givenUp := make(chan Event, 1)
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.offsets = store
+	updater.acks = newAckTracker(20*time.Millisecond, 2)
+	updater.onGiveUp = func(event Event) { givenUp <- event }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan Event)
+	go updater.RunUpdatesCheck(ctx, ch)
+
+	// the handler always fails on the event 3
+	go func() {
+		for event := range ch {
+			if event.EventID != 3 {
+				event.Ack()
+			}
+		}
+	}()
+
+	select {
+	case event := <-givenUp:
+		assert.Equal(3, event.EventID)
+		assert.Equal(2, event.DeliveryAttempt())
+	case <-time.After(time.Second):
+		require.Fail("the event is not given up")
+	}
+
+	assert.Eventually(func() bool {
+		eventID, _ := store.Load(context.Background())
+		return eventID == 8
+	}, time.Second, time.Millisecond)
+	assert.Equal(0, updater.acks.len())
+}
+
+func TestUpdater_AckRestart(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	lastEventIDs := make(chan string, 10)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventID := r.FormValue("lastEventId")
+		lastEventIDs <- lastEventID
+		if lastEventID != "0" && lastEventID != "2" {
+			<-r.Context().Done()
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.acks = newAckTracker(time.Minute, 0)
+
+	ch, err := updater.Start(context.Background())
+	require.NoError(err)
+	for i := 0; i < 8; i++ {
+		event := <-ch
+		if event.EventID != 3 {
+			event.Ack()
+		}
+	}
+	assert.Equal("0", <-lastEventIDs)
+	assert.Equal("8", <-lastEventIDs)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_ = updater.Stop(stopCtx)
+	assert.Equal(6, updater.acks.len())
+
+	// the unacked event is fetched again and tracked once
+	ch, err = updater.Start(context.Background())
+	require.NoError(err)
+	assert.Equal("2", <-lastEventIDs)
+	for i := 0; i < 8; i++ {
+		event := <-ch
+		assert.Equal(1, event.DeliveryAttempt())
+		event.Ack()
+	}
+	assert.Equal(0, updater.acks.len())
+
+	stopCtx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_ = updater.Stop(stopCtx)
+}
@@ -7,9 +7,12 @@ Crafted with love in @mail for your awesome bots.
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -22,116 +25,293 @@ const (
 // Bot is the main structure for interaction with API.
 // All fields are private, you can configure bot using config arguments in NewBot func.
 type Bot struct {
-	client  *Client
-	updater *Updater
-	logger  *logrus.Logger
-	Info    *BotInfo
+	client     *Client
+	updater    *Updater
+	logger     Logger
+	bufferSize int
+
+	// Info about the bot, it is loaded by NewBot or, with BotSkipGetInfo, on the first call of Self.
+	// Self and Refresh set it under the lock, read it with Self if they may run concurrently.
+	Info   *BotInfo
+	infoMu sync.Mutex
+}
+
+// PingResult is the result of the health check of the bot
+type PingResult struct {
+	// Round-trip time of the request
+	Latency time.Duration
+
+	// Reachable is true if the API responded, even with an error
+	Reachable bool
+
+	// TokenValid is true if the API accepted the token,
+	// the error of Ping matches ErrInvalidToken if the token was rejected
+	TokenValid bool
 }
 
 // AutosubscribeToThreads toggles thread auto-subscription behaviour for the specified chat.
 // enable – turn the feature on/off.
 // withExisting – if true, the bot will also subscribe to already existing threads.
 func (b *Bot) AutosubscribeToThreads(chatID string, enable, withExisting bool) error {
-	return b.client.AutosubscribeToThreads(chatID, enable, withExisting)
+	return b.AutosubscribeToThreadsWithContext(context.Background(), chatID, enable, withExisting)
+}
+
+// AutosubscribeToThreadsWithContext is the same as AutosubscribeToThreads, but the request is bound to ctx
+func (b *Bot) AutosubscribeToThreadsWithContext(ctx context.Context, chatID string, enable, withExisting bool) error {
+	return b.client.AutosubscribeToThreadsWithContext(ctx, chatID, enable, withExisting)
 }
 
 // AddThread adds a new thread to the specified chat and returns the thread ID.
 func (b *Bot) AddThread(chatID, msgID string) (*Thread, error) {
-	return b.client.AddThread(chatID, msgID)
+	return b.AddThreadWithContext(context.Background(), chatID, msgID)
+}
+
+// AddThreadWithContext is the same as AddThread, but the request is bound to ctx
+func (b *Bot) AddThreadWithContext(ctx context.Context, chatID, msgID string) (*Thread, error) {
+	return b.client.AddThreadWithContext(ctx, chatID, msgID)
 }
 
 // GetThreadSubscribers gets the subscribers list for a thread.
 // Either cursor or pageSize must be provided.
 func (b *Bot) GetThreadSubscribers(threadID string, cursor string, pageSize int) (*ThreadSubscribers, error) {
-	return b.client.GetThreadSubscribers(threadID, cursor, pageSize)
+	return b.GetThreadSubscribersWithContext(context.Background(), threadID, cursor, pageSize)
+}
+
+// GetThreadSubscribersWithContext is the same as GetThreadSubscribers, but the request is bound to ctx
+func (b *Bot) GetThreadSubscribersWithContext(ctx context.Context, threadID string, cursor string, pageSize int) (*ThreadSubscribers, error) {
+	return b.client.GetThreadSubscribersWithContext(ctx, threadID, cursor, pageSize)
 }
 
 // GetInfo returns information about bot:
 // id, name, about, avatar
 func (b *Bot) GetInfo() (*BotInfo, error) {
-	return b.client.GetInfo()
+	return b.GetInfoWithContext(context.Background())
+}
+
+// GetInfoWithContext is the same as GetInfo, but the request is bound to ctx
+func (b *Bot) GetInfoWithContext(ctx context.Context) (*BotInfo, error) {
+	return b.client.GetInfoWithContext(ctx)
+}
+
+// Self returns information about the bot loaded once and cached in Info.
+// It makes the request only if Info is not loaded yet, e.g. with BotSkipGetInfo.
+func (b *Bot) Self(ctx context.Context) (*BotInfo, error) {
+	b.infoMu.Lock()
+	defer b.infoMu.Unlock()
+
+	if b.Info != nil {
+		return b.Info, nil
+	}
+
+	return b.refresh(ctx)
+}
+
+// Refresh reloads information about the bot into Info
+func (b *Bot) Refresh(ctx context.Context) error {
+	b.infoMu.Lock()
+	defer b.infoMu.Unlock()
+
+	_, err := b.refresh(ctx)
+	return err
+}
+
+func (b *Bot) refresh(ctx context.Context) (*BotInfo, error) {
+	info, err := b.client.GetInfoWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get info about bot: %w", err)
+	}
+
+	b.Info = info
+	return info, nil
+}
+
+// Ping checks that the API is reachable and accepts the token.
+// The error is nil only if the check passed, PingResult tells what failed otherwise.
+// The request is made once regardless of the retry policy, so Latency is the time of a single attempt.
+func (b *Bot) Ping(ctx context.Context) (PingResult, error) {
+	start := time.Now()
+	_, err := b.client.GetInfoWithContext(withoutRetry(ctx))
+	result := PingResult{Latency: time.Since(start)}
+
+	apiErr := &APIError{}
+	switch {
+	case err == nil:
+		result.Reachable = true
+		result.TokenValid = true
+	case errors.As(err, &apiErr):
+		result.Reachable = true
+	}
+
+	return result, err
 }
 
 // GetChatInfo returns information about chat:
 // id, type, title, public, group, inviteLink, admins
 func (b *Bot) GetChatInfo(chatID string) (*Chat, error) {
-	return b.client.GetChatInfo(chatID)
+	return b.GetChatInfoWithContext(context.Background(), chatID)
+}
+
+// GetChatInfoWithContext is the same as GetChatInfo, but the request is bound to ctx
+func (b *Bot) GetChatInfoWithContext(ctx context.Context, chatID string) (*Chat, error) {
+	return b.client.GetChatInfoWithContext(ctx, chatID)
 }
 
 // SendChatActions sends an actions like "typing, looking"
 func (b *Bot) SendChatActions(chatID string, actions ...ChatAction) error {
-	return b.client.SendChatActions(chatID, actions...)
+	return b.SendChatActionsWithContext(context.Background(), chatID, actions...)
+}
+
+// SendChatActionsWithContext is the same as SendChatActions, but the request is bound to ctx
+func (b *Bot) SendChatActionsWithContext(ctx context.Context, chatID string, actions ...ChatAction) error {
+	return b.client.SendChatActionsWithContext(ctx, chatID, actions...)
 }
 
 // GetChatAdmins returns chat admins list with fields:
 // userID, creator flag
 func (b *Bot) GetChatAdmins(chatID string) ([]ChatMember, error) {
-	return b.client.GetChatAdmins(chatID)
+	return b.GetChatAdminsWithContext(context.Background(), chatID)
+}
+
+// GetChatAdminsWithContext is the same as GetChatAdmins, but the request is bound to ctx
+func (b *Bot) GetChatAdminsWithContext(ctx context.Context, chatID string) ([]ChatMember, error) {
+	return b.client.GetChatAdminsWithContext(ctx, chatID)
 }
 
 // GetChatMem returns chat members list with fields:
 // userID, creator flag, admin flag
 func (b *Bot) GetChatMembers(chatID string) ([]ChatMember, error) {
-	return b.client.GetChatMembers(chatID)
+	return b.GetChatMembersWithContext(context.Background(), chatID)
+}
+
+// GetChatMembersWithContext is the same as GetChatMembers, but the request is bound to ctx
+func (b *Bot) GetChatMembersWithContext(ctx context.Context, chatID string) ([]ChatMember, error) {
+	return b.client.GetChatMembersWithContext(ctx, chatID)
 }
 
 // GetChatBlockedUsers returns chat blocked users list:
 // userID
 func (b *Bot) GetChatBlockedUsers(chatID string) ([]User, error) {
-	return b.client.GetChatBlockedUsers(chatID)
+	return b.GetChatBlockedUsersWithContext(context.Background(), chatID)
+}
+
+// GetChatBlockedUsersWithContext is the same as GetChatBlockedUsers, but the request is bound to ctx
+func (b *Bot) GetChatBlockedUsersWithContext(ctx context.Context, chatID string) ([]User, error) {
+	return b.client.GetChatBlockedUsersWithContext(ctx, chatID)
 }
 
 // GetChatPendingUsers returns chat join pending users list:
 // userID
 func (b *Bot) GetChatPendingUsers(chatID string) ([]User, error) {
-	return b.client.GetChatPendingUsers(chatID)
+	return b.GetChatPendingUsersWithContext(context.Background(), chatID)
+}
+
+// GetChatPendingUsersWithContext is the same as GetChatPendingUsers, but the request is bound to ctx
+func (b *Bot) GetChatPendingUsersWithContext(ctx context.Context, chatID string) ([]User, error) {
+	return b.client.GetChatPendingUsersWithContext(ctx, chatID)
 }
 
 // BlockChatUser blocks user and removes him from chat.
 // If deleteLastMessages is true, the messages written recently will be deleted
 func (b *Bot) BlockChatUser(chatID, userID string, deleteLastMessages bool) error {
-	return b.client.BlockChatUser(chatID, userID, deleteLastMessages)
+	return b.BlockChatUserWithContext(context.Background(), chatID, userID, deleteLastMessages)
+}
+
+// BlockChatUserWithContext is the same as BlockChatUser, but the request is bound to ctx
+func (b *Bot) BlockChatUserWithContext(ctx context.Context, chatID, userID string, deleteLastMessages bool) error {
+	return b.client.BlockChatUserWithContext(ctx, chatID, userID, deleteLastMessages)
 }
 
 // UnblockChatUser unblocks user in chat
 func (b *Bot) UnblockChatUser(chatID, userID string) error {
-	return b.client.UnblockChatUser(chatID, userID)
+	return b.UnblockChatUserWithContext(context.Background(), chatID, userID)
+}
+
+// UnblockChatUserWithContext is the same as UnblockChatUser, but the request is bound to ctx
+func (b *Bot) UnblockChatUserWithContext(ctx context.Context, chatID, userID string) error {
+	return b.client.UnblockChatUserWithContext(ctx, chatID, userID)
 }
 
 // DeleteChatMembers removes multiple members from chat
 func (b *Bot) DeleteChatMembers(chatID string, members []string) error {
-	return b.client.DeleteChatMembers(chatID, members)
+	return b.DeleteChatMembersWithContext(context.Background(), chatID, members)
+}
+
+// DeleteChatMembersWithContext is the same as DeleteChatMembers, but the request is bound to ctx
+func (b *Bot) DeleteChatMembersWithContext(ctx context.Context, chatID string, members []string) error {
+	return b.client.DeleteChatMembersWithContext(ctx, chatID, members)
 }
 
 // AddChatMembers adds multiple members to chat
 func (b *Bot) AddChatMembers(chatID string, members []string) error {
-	return b.client.AddChatMembers(chatID, members)
+	return b.AddChatMembersWithContext(context.Background(), chatID, members)
+}
+
+// AddChatMembersWithContext is the same as AddChatMembers, but the request is bound to ctx
+func (b *Bot) AddChatMembersWithContext(ctx context.Context, chatID string, members []string) error {
+	return b.client.AddChatMembersWithContext(ctx, chatID, members)
 }
 
 // ResolveChatJoinRequests resolves pending join requests for specified user or all pending users
 func (b *Bot) ResolveChatJoinRequests(chatID, userID string, accept, everyone bool) error {
-	return b.client.ResolveChatPending(chatID, userID, accept, everyone)
+	return b.ResolveChatJoinRequestsWithContext(context.Background(), chatID, userID, accept, everyone)
+}
+
+// ResolveChatJoinRequestsWithContext is the same as ResolveChatJoinRequests, but the request is bound to ctx
+func (b *Bot) ResolveChatJoinRequestsWithContext(ctx context.Context, chatID, userID string, accept, everyone bool) error {
+	return b.client.ResolveChatPendingWithContext(ctx, chatID, userID, accept, everyone)
 }
 
 // SetChatTitle changes chat title
 func (b *Bot) SetChatTitle(chatID, title string) error {
-	return b.client.SetChatTitle(chatID, title)
+	return b.SetChatTitleWithContext(context.Background(), chatID, title)
+}
+
+// SetChatTitleWithContext is the same as SetChatTitle, but the request is bound to ctx
+func (b *Bot) SetChatTitleWithContext(ctx context.Context, chatID, title string) error {
+	return b.client.SetChatTitleWithContext(ctx, chatID, title)
 }
 
 // SetChatAbout changes chat about
 func (b *Bot) SetChatAbout(chatID, about string) error {
-	return b.client.SetChatAbout(chatID, about)
+	return b.SetChatAboutWithContext(context.Background(), chatID, about)
+}
+
+// SetChatAboutWithContext is the same as SetChatAbout, but the request is bound to ctx
+func (b *Bot) SetChatAboutWithContext(ctx context.Context, chatID, about string) error {
+	return b.client.SetChatAboutWithContext(ctx, chatID, about)
 }
 
 // SetChatRules changes chat rules
 func (b *Bot) SetChatRules(chatID, rules string) error {
-	return b.client.SetChatRules(chatID, rules)
+	return b.SetChatRulesWithContext(context.Background(), chatID, rules)
+}
+
+// SetChatRulesWithContext is the same as SetChatRules, but the request is bound to ctx
+func (b *Bot) SetChatRulesWithContext(ctx context.Context, chatID, rules string) error {
+	return b.client.SetChatRulesWithContext(ctx, chatID, rules)
 }
 
 // GetFileInfo returns information about file:
 // id, type, size, filename, url
 func (b *Bot) GetFileInfo(fileID string) (*File, error) {
-	return b.client.GetFileInfo(fileID)
+	return b.GetFileInfoWithContext(context.Background(), fileID)
+}
+
+// GetFileInfoWithContext is the same as GetFileInfo, but the request is bound to ctx
+func (b *Bot) GetFileInfoWithContext(ctx context.Context, fileID string) (*File, error) {
+	return b.client.GetFileInfoWithContext(ctx, fileID)
+}
+
+// DownloadFile writes the content of the file to w using the bot's http client and retry policy.
+// The size of the content is checked against File.Size, use DownloadMaxSize to limit it.
+func (b *Bot) DownloadFile(ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption) (*File, error) {
+	return b.client.DownloadFileWithContext(ctx, fileID, w, opts...)
+}
+
+// DownloadToPath saves the file to path.
+// If the previous download of the same file to the same path was interrupted, it is resumed.
+func (b *Bot) DownloadToPath(ctx context.Context, fileID, path string, opts ...DownloadOption) (*File, error) {
+	return b.client.DownloadToPathWithContext(ctx, fileID, path, opts...)
 }
 
 // NewMessage returns new message
@@ -196,6 +376,17 @@ func (b *Bot) NewFileMessage(chatID string, file *os.File) *Message {
 	}
 }
 
+// NewFileMessageFromReader returns new file message with the data streamed from the reader
+func (b *Bot) NewFileMessageFromReader(chatID, name string, reader io.Reader) *Message {
+	return &Message{
+		client:      b.client,
+		Chat:        Chat{ID: chatID},
+		Reader:      reader,
+		FileName:    name,
+		ContentType: OtherFile,
+	}
+}
+
 // NewFileMessageByFileID returns new message with previously uploaded file id
 func (b *Bot) NewFileMessageByFileID(chatID, fileID string) *Message {
 	return &Message{
@@ -216,6 +407,17 @@ func (b *Bot) NewVoiceMessage(chatID string, file *os.File) *Message {
 	}
 }
 
+// NewVoiceMessageFromReader returns new voice message with the data streamed from the reader
+func (b *Bot) NewVoiceMessageFromReader(chatID, name string, reader io.Reader) *Message {
+	return &Message{
+		client:      b.client,
+		Chat:        Chat{ID: chatID},
+		Reader:      reader,
+		FileName:    name,
+		ContentType: Voice,
+	}
+}
+
 // NewVoiceMessageByFileID returns new message with previously uploaded voice file id
 func (b *Bot) NewVoiceMessageByFileID(chatID, fileID string) *Message {
 	return &Message{
@@ -258,20 +460,36 @@ func (b *Bot) NewChat(id string) *Chat {
 // SendMessage sends a message, passed as an argument.
 // This method fills the argument with ID of sent message and returns an error if any.
 func (b *Bot) SendMessage(message *Message) error {
+	return b.SendMessageWithContext(context.Background(), message)
+}
+
+// SendMessageWithContext is the same as SendMessage, but the request is bound to ctx
+func (b *Bot) SendMessageWithContext(ctx context.Context, message *Message) error {
 	message.client = b.client
-	return message.Send()
+	return message.SendWithContext(ctx)
 }
 
 // EditMessage edit a message passed as an argument.
 func (b *Bot) EditMessage(message *Message) error {
-	return b.client.EditMessage(message)
+	return b.EditMessageWithContext(context.Background(), message)
+}
+
+// EditMessageWithContext is the same as EditMessage, but the request is bound to ctx
+func (b *Bot) EditMessageWithContext(ctx context.Context, message *Message) error {
+	return b.client.EditMessageWithContext(ctx, message)
+}
+
+// Updater returns the updater of the bot to control polling of events
+// with Start, Stop, Pause and Resume and to read its Status
+func (b *Bot) Updater() *Updater {
+	return b.updater
 }
 
 // GetUpdatesChannel returns a channel, which will be filled with events.
 // You can pass cancellable context there and stop receiving events.
 // The channel will be closed after context cancellation.
 func (b *Bot) GetUpdatesChannel(ctx context.Context) <-chan Event {
-	updates := make(chan Event)
+	updates := make(chan Event, b.bufferSize)
 
 	go b.updater.RunUpdatesCheck(ctx, updates)
 
@@ -282,42 +500,111 @@ func (b *Bot) GetUpdatesChannel(ctx context.Context) <-chan Event {
 // All communications with bot API must go through Bot struct.
 // In general you don't need to configure this bot, therefore all options are optional arguments.
 func NewBot(token string, opts ...BotOption) (*Bot, error) {
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp:   true,
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
-
-	apiURL := defaultAPIURL
-	debug := defaultDebug
-	client := *http.DefaultClient
-	for _, option := range opts {
-		switch option.Type() {
-		case "api_url":
-			apiURL = option.Value().(string)
-		case "debug":
-			debug = option.Value().(bool)
-		case "http_client":
-			client = option.Value().(http.Client)
+	config, err := newBotConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := config.logger
+	if logger == nil {
+		logrusLogger := logrus.New()
+		logrusLogger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "2006-01-02 15:04:05",
+		})
+
+		if config.debug {
+			logrusLogger.SetLevel(logrus.DebugLevel)
+		}
+
+		logger = NewLogrusLogger(logrusLogger)
+	}
+
+	if config.transport != nil {
+		transport, err := NewTransport(*config.transport)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure transport: %w", err)
 		}
+
+		client := *config.client
+		client.Transport = transport
+		config.client = &client
 	}
 
-	if debug {
-		logger.SetLevel(logrus.DebugLevel)
+	middlewares := config.middlewares
+	if config.metrics != nil {
+		middlewares = append(middlewares, config.metrics.Middleware())
 	}
 
-	tgClient := NewCustomClient(&client, apiURL, token, logger)
-	updater := NewUpdater(tgClient, 0, logger)
+	tgClient := newClient(config.client, config.apiURL, token, logger)
+	tgClient.retryPolicy = config.retryPolicy
+	tgClient.rateLimiter = config.rateLimiter
+	tgClient.postForm = config.postForm
+	tgClient.postThreshold = config.postThreshold
+	tgClient.redact = config.redactPolicy
+	tgClient.middlewares = middlewares
+	tgClient.tracer = config.tracer
+	tgClient.userAgent = config.userAgent
+	tgClient.requestTimeout = config.requestTimeout
+	tgClient.uploadTimeout = config.uploadTimeout
+	tgClient.tokenProvider = config.tokenProvider
+
+	updater := newUpdater(tgClient, pollTimeSeconds(config.pollTime), logger)
+	if pollTime := time.Duration(updater.PollTime) * time.Second; config.client.Timeout > 0 && config.client.Timeout <= pollTime {
+		// every poll would time out, so the poll is shortened to fit into the client timeout
+		updater.PollTime = clientPollTime(config.client.Timeout)
+		logger.Warn("timeout of the http client is not longer than the poll time, the poll time is lowered, "+
+			"use BotRequestTimeout to limit requests", LogFields{
+			"clientTimeout": config.client.Timeout,
+			"pollTime":      pollTime,
+			"newPollTime":   time.Duration(updater.PollTime) * time.Second,
+		})
+	}
+	updater.metrics = config.metrics
+	updater.offsets = config.offsetStore
+	updater.bufferSize = config.bufferSize
+	updater.onUnknownType = config.onUnknownType
+	if config.pollPolicy != nil {
+		updater.poll = config.pollPolicy.withDefaults()
+	}
+	if config.ackTimeout > 0 {
+		updater.acks = newAckTracker(config.ackTimeout, config.ackMaxAttempts)
+		updater.onGiveUp = config.onAckGiveUp
+	}
+
+	bot := &Bot{
+		client:     tgClient,
+		updater:    updater,
+		logger:     logger,
+		bufferSize: config.bufferSize,
+	}
+
+	if config.skipGetInfo {
+		return bot, nil
+	}
 
 	info, err := tgClient.GetInfo()
 	if err != nil {
-		return nil, fmt.Errorf("cannot get info about bot: %s", err)
+		return nil, fmt.Errorf("cannot get info about bot: %w", err)
 	}
+	bot.Info = info
 
-	return &Bot{
-		client:  tgClient,
-		updater: updater,
-		logger:  logger,
-		Info:    info,
-	}, nil
+	return bot, nil
+}
+
+// pollTimeSeconds rounds the poll time up to whole seconds, zero means the default
+func pollTimeSeconds(pollTime time.Duration) int {
+	if pollTime <= 0 {
+		return 0
+	}
+	return int((pollTime + time.Second - 1) / time.Second)
+}
+
+// clientPollTime returns the poll time in seconds which leaves the half of the client timeout
+// for the network, at least 1 second
+func clientPollTime(timeout time.Duration) int {
+	if seconds := int(timeout / 2 / time.Second); seconds > 0 {
+		return seconds
+	}
+	return 1
 }
@@ -0,0 +1,125 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBot_Lazy(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"ok":true,"userId":"test_bot","nick":"test_bot"}`))
+	}))
+	defer func() { testServer.Close() }()
+
+	bot, err := NewBot("test_token", BotApiURL(testServer.URL), BotSkipGetInfo(true))
+	require.NoError(err)
+	assert.Nil(bot.Info)
+	assert.EqualValues(0, atomic.LoadInt32(&calls))
+
+	info, err := bot.Self(context.Background())
+	require.NoError(err)
+	assert.Equal("test_bot", info.ID)
+	assert.Same(info, bot.Info)
+
+	_, err = bot.Self(context.Background())
+	require.NoError(err)
+	assert.EqualValues(1, atomic.LoadInt32(&calls))
+
+	require.NoError(bot.Refresh(context.Background()))
+	assert.EqualValues(2, atomic.LoadInt32(&calls))
+	refreshed, err := bot.Self(context.Background())
+	require.NoError(err)
+	assert.NotSame(info, refreshed)
+	assert.Same(refreshed, bot.Info)
+
+	// Self and Refresh are safe to call concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(bot.Refresh(context.Background()))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := bot.Self(context.Background())
+			assert.NoError(err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBot_Ping(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"userId":"test_bot"}`))
+	}))
+	defer func() { testServer.Close() }()
+
+	tests := []struct {
+		name       string
+		apiURL     string
+		token      string
+		wantErr    error
+		reachable  bool
+		tokenValid bool
+	}{
+		{"ok", testServer.URL, "test_token", nil, true, true},
+		{"invalid_token", testServer.URL, "wrong_token", ErrInvalidToken, true, false},
+		{"unreachable", "http://127.0.0.1:0", "test_token", ErrTransport, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, err := NewBot(tt.token, BotApiURL(tt.apiURL), BotSkipGetInfo(true))
+			require.NoError(t, err)
+
+			result, err := bot.Ping(context.Background())
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.reachable, result.Reachable)
+			assert.Equal(t, tt.tokenValid, result.TokenValid)
+			assert.Greater(t, result.Latency, time.Duration(0))
+		})
+	}
+}
+
+func TestBot_PingWithoutRetry(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer func() { testServer.Close() }()
+
+	bot, err := NewBot("test_token",
+		BotApiURL(testServer.URL),
+		BotSkipGetInfo(true),
+		BotRetryPolicy(&ExponentialBackoff{MaxAttempts: 5, InitialInterval: time.Second}),
+	)
+	require.NoError(t, err)
+
+	result, err := bot.Ping(context.Background())
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	assert.Less(t, result.Latency, time.Second)
+	assert.Nil(t, bot.Info)
+}
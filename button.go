@@ -1,5 +1,7 @@
 package botgolang
 
+import "context"
+
 //go:generate easyjson -all button.go
 
 // Button represents a button in inline keyboard
@@ -73,5 +75,10 @@ type ButtonResponse struct {
 // Send method sends your response message.
 // Make sure you have QueryID in your ButtonResponse.
 func (cl *ButtonResponse) Send() error {
-	return cl.client.SendAnswerCallbackQuery(cl)
+	return cl.SendWithContext(context.Background())
+}
+
+// SendWithContext is the same as Send, but the request is bound to ctx
+func (cl *ButtonResponse) SendWithContext(ctx context.Context) error {
+	return cl.client.SendAnswerCallbackQueryWithContext(ctx, cl)
 }
@@ -1,5 +1,7 @@
 package botgolang
 
+import "context"
+
 //go:generate easyjson -all chat.go
 
 type ChatAction = string
@@ -71,81 +73,161 @@ func (c *Chat) resolveID() string {
 // or every 10 seconds if the actions have not changed. After sending a
 // request without active action, you should not re-notify of their absence.
 func (c *Chat) SendActions(actions ...ChatAction) error {
-	return c.client.SendChatActions(c.resolveID(), actions...)
+	return c.SendActionsWithContext(context.Background(), actions...)
+}
+
+// SendActionsWithContext is the same as SendActions, but the request is bound to ctx
+func (c *Chat) SendActionsWithContext(ctx context.Context, actions ...ChatAction) error {
+	return c.client.SendChatActionsWithContext(ctx, c.resolveID(), actions...)
 }
 
 // Get chat administrators list
 func (c *Chat) GetAdmins() ([]ChatMember, error) {
-	return c.client.GetChatAdmins(c.ID)
+	return c.GetAdminsWithContext(context.Background())
+}
+
+// GetAdminsWithContext is the same as GetAdmins, but the request is bound to ctx
+func (c *Chat) GetAdminsWithContext(ctx context.Context) ([]ChatMember, error) {
+	return c.client.GetChatAdminsWithContext(ctx, c.ID)
 }
 
 // Get chat members list
 func (c *Chat) GetMembers() ([]ChatMember, error) {
-	return c.client.GetChatMembers(c.ID)
+	return c.GetMembersWithContext(context.Background())
+}
+
+// GetMembersWithContext is the same as GetMembers, but the request is bound to ctx
+func (c *Chat) GetMembersWithContext(ctx context.Context) ([]ChatMember, error) {
+	return c.client.GetChatMembersWithContext(ctx, c.ID)
 }
 
 // Get chat blocked users list
 func (c *Chat) GetBlockedUsers() ([]User, error) {
-	return c.client.GetChatBlockedUsers(c.ID)
+	return c.GetBlockedUsersWithContext(context.Background())
+}
+
+// GetBlockedUsersWithContext is the same as GetBlockedUsers, but the request is bound to ctx
+func (c *Chat) GetBlockedUsersWithContext(ctx context.Context) ([]User, error) {
+	return c.client.GetChatBlockedUsersWithContext(ctx, c.ID)
 }
 
 // Get chat join pending users list
 func (c *Chat) GetPendingUsers() ([]User, error) {
-	return c.client.GetChatPendingUsers(c.ID)
+	return c.GetPendingUsersWithContext(context.Background())
+}
+
+// GetPendingUsersWithContext is the same as GetPendingUsers, but the request is bound to ctx
+func (c *Chat) GetPendingUsersWithContext(ctx context.Context) ([]User, error) {
+	return c.client.GetChatPendingUsersWithContext(ctx, c.ID)
 }
 
 // DeleteMembers removes members from chat
 func (c *Chat) DeleteMembers(members []string) error {
-	return c.client.DeleteChatMembers(c.ID, members)
+	return c.DeleteMembersWithContext(context.Background(), members)
+}
+
+// DeleteMembersWithContext is the same as DeleteMembers, but the request is bound to ctx
+func (c *Chat) DeleteMembersWithContext(ctx context.Context, members []string) error {
+	return c.client.DeleteChatMembersWithContext(ctx, c.ID, members)
 }
 
 // AddMembers adds members to chat
 func (c *Chat) AddMembers(members []string) error {
-	return c.client.AddChatMembers(c.ID, members)
+	return c.AddMembersWithContext(context.Background(), members)
+}
+
+// AddMembersWithContext is the same as AddMembers, but the request is bound to ctx
+func (c *Chat) AddMembersWithContext(ctx context.Context, members []string) error {
+	return c.client.AddChatMembersWithContext(ctx, c.ID, members)
 }
 
 // Block user and remove him from chat.
 // If deleteLastMessages is true, the messages written recently will be deleted
 func (c *Chat) BlockUser(userID string, deleteLastMessages bool) error {
-	return c.client.BlockChatUser(c.ID, userID, deleteLastMessages)
+	return c.BlockUserWithContext(context.Background(), userID, deleteLastMessages)
+}
+
+// BlockUserWithContext is the same as BlockUser, but the request is bound to ctx
+func (c *Chat) BlockUserWithContext(ctx context.Context, userID string, deleteLastMessages bool) error {
+	return c.client.BlockChatUserWithContext(ctx, c.ID, userID, deleteLastMessages)
 }
 
 // Unblock user in chat (but not add him back)
 func (c *Chat) UnblockUser(userID string) error {
-	return c.client.UnblockChatUser(c.ID, userID)
+	return c.UnblockUserWithContext(context.Background(), userID)
+}
+
+// UnblockUserWithContext is the same as UnblockUser, but the request is bound to ctx
+func (c *Chat) UnblockUserWithContext(ctx context.Context, userID string) error {
+	return c.client.UnblockChatUserWithContext(ctx, c.ID, userID)
 }
 
 // ResolveJoinRequest resolve specific user chat join request
 func (c *Chat) ResolveJoinRequest(userID string, accept bool) error {
-	return c.client.ResolveChatPending(c.ID, userID, accept, false)
+	return c.ResolveJoinRequestWithContext(context.Background(), userID, accept)
+}
+
+// ResolveJoinRequestWithContext is the same as ResolveJoinRequest, but the request is bound to ctx
+func (c *Chat) ResolveJoinRequestWithContext(ctx context.Context, userID string, accept bool) error {
+	return c.client.ResolveChatPendingWithContext(ctx, c.ID, userID, accept, false)
 }
 
 // ResolveAllJoinRequest resolve all chat join requests
 func (c *Chat) ResolveAllJoinRequests(accept bool) error {
-	return c.client.ResolveChatPending(c.ID, "", accept, true)
+	return c.ResolveAllJoinRequestsWithContext(context.Background(), accept)
+}
+
+// ResolveAllJoinRequestsWithContext is the same as ResolveAllJoinRequests, but the request is bound to ctx
+func (c *Chat) ResolveAllJoinRequestsWithContext(ctx context.Context, accept bool) error {
+	return c.client.ResolveChatPendingWithContext(ctx, c.ID, "", accept, true)
 }
 
 // SetTitle changes chat title
 func (c *Chat) SetTitle(title string) error {
-	return c.client.SetChatTitle(c.ID, title)
+	return c.SetTitleWithContext(context.Background(), title)
+}
+
+// SetTitleWithContext is the same as SetTitle, but the request is bound to ctx
+func (c *Chat) SetTitleWithContext(ctx context.Context, title string) error {
+	return c.client.SetChatTitleWithContext(ctx, c.ID, title)
 }
 
 // SetAbout changes chat about
 func (c *Chat) SetAbout(about string) error {
-	return c.client.SetChatAbout(c.ID, about)
+	return c.SetAboutWithContext(context.Background(), about)
+}
+
+// SetAboutWithContext is the same as SetAbout, but the request is bound to ctx
+func (c *Chat) SetAboutWithContext(ctx context.Context, about string) error {
+	return c.client.SetChatAboutWithContext(ctx, c.ID, about)
 }
 
 // SetRules changes chat rules
 func (c *Chat) SetRules(rules string) error {
-	return c.client.SetChatRules(c.ID, rules)
+	return c.SetRulesWithContext(context.Background(), rules)
+}
+
+// SetRulesWithContext is the same as SetRules, but the request is bound to ctx
+func (c *Chat) SetRulesWithContext(ctx context.Context, rules string) error {
+	return c.client.SetChatRulesWithContext(ctx, c.ID, rules)
 }
 
 // AddThread adds a new thread to the specified chat and returns the thread ID
 func (c *Chat) AddThread(msgID string) (*Thread, error) {
-	return c.client.AddThread(c.ID, msgID)
+	return c.AddThreadWithContext(context.Background(), msgID)
+}
+
+// AddThreadWithContext is the same as AddThread, but the request is bound to ctx
+func (c *Chat) AddThreadWithContext(ctx context.Context, msgID string) (*Thread, error) {
+	return c.client.AddThreadWithContext(ctx, c.ID, msgID)
 }
 
 // AutosubscribeToThreads toggles thread auto-subscription for the chat
 func (c *Chat) AutosubscribeToThreads(enable, withExisting bool) error {
-	return c.client.AutosubscribeToThreads(c.ID, enable, withExisting)
+	return c.AutosubscribeToThreadsWithContext(context.Background(), enable, withExisting)
+}
+
+// AutosubscribeToThreadsWithContext is the same as AutosubscribeToThreads, but the request is bound to ctx
+func (c *Chat) AutosubscribeToThreadsWithContext(ctx context.Context, enable, withExisting bool) error {
+	return c.client.AutosubscribeToThreadsWithContext(ctx, c.ID, enable, withExisting)
 }
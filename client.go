@@ -1,25 +1,53 @@
 package botgolang
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 type Client struct {
-	client  *http.Client
-	token   string
-	baseURL string
-	logger  *logrus.Logger
+	client      *http.Client
+	token       string
+	baseURL     string
+	logger      Logger
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+
+	// send params as a form body instead of the query string
+	postForm bool
+
+	// send params as a form body if the encoded params are longer than this
+	postThreshold int
+
+	// redaction of sensitive data in logs
+	redact RedactPolicy
+
+	// middlewares around every call, the first one is the outermost
+	middlewares []Middleware
+
+	// tracer of calls and events
+	tracer Tracer
+
+	// source of the token, the static token is used if nil
+	tokenProvider TokenProvider
+
+	// User-Agent header of requests, the default one of http client if empty
+	userAgent string
+
+	// timeouts of every attempt of requests and uploads, no timeout if zero
+	requestTimeout time.Duration
+	uploadTimeout  time.Duration
 }
 
 func (c *Client) Do(path string, params url.Values, file *os.File) ([]byte, error) {
@@ -27,94 +55,270 @@ func (c *Client) Do(path string, params url.Values, file *os.File) ([]byte, erro
 }
 
 func (c *Client) DoWithContext(ctx context.Context, path string, params url.Values, file *os.File) ([]byte, error) {
+	return c.call(ctx, path, params, osFileUpload(file))
+}
+
+type noRetryKey struct{}
+
+// withoutRetry makes the request of ctx fail after the first attempt regardless of the retry policy
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+func (c *Client) doWithRetry(ctx context.Context, call *Call, upload *fileUpload) ([]byte, error) {
+	path, params := call.Path, call.Params
+
+	retryPolicy := c.retryPolicy
+	if noRetry, _ := ctx.Value(noRetryKey{}).(bool); noRetry {
+		retryPolicy = nil
+	}
+
+	var offset int64
+	var seeker io.Seeker
+	if upload != nil && retryPolicy != nil {
+		// the file cannot be read twice if it is not seekable, so there is nothing to retry
+		if s, ok := upload.reader.(io.Seeker); ok {
+			if current, err := s.Seek(0, io.SeekCurrent); err == nil {
+				offset, seeker = current, s
+			}
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := c.waitRateLimit(ctx, path, params); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := c.attemptContext(ctx, call, upload)
+		response, err := c.do(attemptCtx, call, upload)
+		cancel()
+		if err == nil {
+			return response, nil
+		}
+
+		c.reportRateLimit(path, params, err)
+
+		if retryPolicy == nil || (upload != nil && seeker == nil) {
+			return response, err
+		}
+
+		delay, retry := retryPolicy.NextRetry(attempt, path, err)
+		if !retry || ctx.Err() != nil {
+			return response, err
+		}
+
+		if seeker != nil {
+			if _, seekErr := seeker.Seek(offset, io.SeekStart); seekErr != nil {
+				return response, err
+			}
+		}
+
+		c.logger.Warn("request failed, retrying", LogFields{
+			"err":     err,
+			"path":    path,
+			"attempt": attempt,
+			"delay":   delay,
+		})
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return response, err
+		}
+	}
+}
+
+// attemptContext limits the attempt of the request by the configured timeout
+func (c *Client) attemptContext(ctx context.Context, call *Call, upload *fileUpload) (context.Context, context.CancelFunc) {
+	timeout := c.requestTimeout
+	if upload != nil {
+		timeout = c.uploadTimeout
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	if call.Path == "/events/get" {
+		if pollTime, err := strconv.Atoi(call.Params.Get("pollTime")); err == nil {
+			timeout += time.Duration(pollTime) * time.Second
+		}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (c *Client) do(ctx context.Context, call *Call, upload *fileUpload) ([]byte, error) {
+	path, params := call.Path, call.Params
+
 	apiURL, err := url.Parse(c.baseURL + path)
-	params.Set("token", c.token)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse url: %w", err)
+	}
 
+	token, err := c.currentToken()
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse url: %s", err)
+		return nil, err
+	}
+
+	// the token is added to a copy, so it doesn't leak into params seen by middlewares
+	query := make(url.Values, len(params)+1)
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("token", token)
+
+	method := http.MethodGet
+	var body io.Reader
+
+	encodedParams := query.Encode()
+	if upload == nil && c.usePostForm(len(encodedParams)) {
+		method = http.MethodPost
+		body = strings.NewReader(encodedParams)
+	} else {
+		apiURL.RawQuery = encodedParams
 	}
 
-	apiURL.RawQuery = params.Encode()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, apiURL.String(), body)
 	if err != nil || req == nil {
-		return nil, fmt.Errorf("cannot init http request: %s", err)
+		return nil, fmt.Errorf("cannot init http request: %w", err)
 	}
 
-	if file != nil {
-		buffer := &bytes.Buffer{}
-		multipartWriter := multipart.NewWriter(buffer)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
-		fileWriter, err := multipartWriter.CreateFormFile("file", file.Name())
-		if err != nil {
-			return nil, fmt.Errorf("cannot create multipart writer: %s", err)
-		}
+	for key, values := range call.Header {
+		req.Header[key] = values
+	}
 
-		_, err = io.Copy(fileWriter, file)
-		if err != nil {
-			return nil, fmt.Errorf("cannot copy file into buffer: %s", err)
-		}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
-		if err := multipartWriter.Close(); err != nil {
-			return nil, fmt.Errorf("cannot close multipartWriter: %s", err)
+	if upload != nil {
+		uploadBody, contentType, length, wait, err := upload.body()
+		if err != nil {
+			return nil, err
 		}
+		// the file is not read after the attempt, so the retry can rewind it
+		defer wait()
 
-		req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
-		req.Body = io.NopCloser(buffer)
+		req.Header.Set("Content-Type", contentType)
+		req.Body = uploadBody
+		req.ContentLength = length
 		req.Method = http.MethodPost
 	}
 
-	c.logger.WithFields(logrus.Fields{
-		"api_url": apiURL,
-	}).Debug("requesting api")
+	if c.logger.DebugEnabled() {
+		fields := LogFields{
+			"api_url":  c.redact.url(apiURL),
+			"method":   req.Method,
+			"endpoint": path,
+			"chatId":   c.redact.redact(c.redact.UserIDs, params.Get("chatId")),
+		}
+		if body != nil {
+			fields["body"] = c.redact.params(params).Encode()
+		}
+		if upload != nil {
+			fields["file"] = c.redact.redact(c.redact.FileNames, upload.name)
+		}
+		c.logger.Debug("requesting api", fields)
+	}
 
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
-		c.logger.WithFields(logrus.Fields{
-			"err": err,
-		}).Error("request error")
-		return []byte{}, fmt.Errorf("cannot make request to bot api: %s", err)
+		// url errors contain the whole url with the token
+		err = c.redact.err(err)
+		c.logger.Error("request error", LogFields{
+			"err":      err,
+			"endpoint": path,
+			"chatId":   c.redact.redact(c.redact.UserIDs, params.Get("chatId")),
+			"latency":  time.Since(start),
+		})
+		return []byte{}, fmt.Errorf("%w: %w", ErrTransport, err)
 	}
 
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			c.logger.WithFields(logrus.Fields{
+			c.logger.Error("cannot close body", LogFields{
 				"err": err,
-			}).Error("cannot close body")
+			})
 		}
 	}()
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.logger.WithFields(logrus.Fields{
-			"err": err,
-		}).Error("cannot read body")
-		return []byte{}, fmt.Errorf("cannot read body: %s", err)
+		c.logger.Error("cannot read body", LogFields{
+			"err":      err,
+			"endpoint": path,
+			"latency":  time.Since(start),
+		})
+		return []byte{}, fmt.Errorf("%w: cannot read body: %w", ErrTransport, err)
 	}
 
-	if c.logger.IsLevelEnabled(logrus.DebugLevel) {
-		c.logger.WithFields(logrus.Fields{
-			"response": responseBody,
-		}).Debug("got response from API")
+	if c.logger.DebugEnabled() {
+		c.logger.Debug("got response from API", LogFields{
+			"response": c.redact.body(responseBody),
+			"endpoint": path,
+			"chatId":   c.redact.redact(c.redact.UserIDs, params.Get("chatId")),
+			"status":   resp.StatusCode,
+			"latency":  time.Since(start),
+		})
 	}
 
+	response := &Response{}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error status from API: %s", resp.Status)
+		// error responses may still contain a description, but they are not guaranteed to be json
+		_ = json.Unmarshal(responseBody, response)
+		apiErr := newAPIError(resp.StatusCode, response.Description, path, params.Get("request-id"))
+		apiErr.RetryAfter = parseRetryAfter(resp.Header)
+		return nil, apiErr
 	}
 
-	response := &Response{}
-
 	if err := json.Unmarshal(responseBody, response); err != nil {
-		return nil, fmt.Errorf("cannot unmarshal json: %s", err)
+		return nil, fmt.Errorf("cannot unmarshal json: %w", err)
 	}
 
 	if !response.OK {
-		return responseBody, fmt.Errorf("error status from API: %s", response.Description)
+		return responseBody, newAPIError(resp.StatusCode, response.Description, path, params.Get("request-id"))
 	}
 
 	return responseBody, nil
 }
 
+// usePostForm reports whether the request params should be sent in the body instead of the query string
+func (c *Client) usePostForm(size int) bool {
+	return c.postForm || (c.postThreshold > 0 && size > c.postThreshold)
+}
+
+func (c *Client) waitRateLimit(ctx context.Context, path string, params url.Values) error {
+	// long polling doesn't count against the limits
+	if c.rateLimiter == nil || path == "/events/get" {
+		return nil
+	}
+
+	if err := c.rateLimiter.Wait(ctx, params.Get("chatId")); err != nil {
+		return fmt.Errorf("cannot wait for rate limiter: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) reportRateLimit(path string, params url.Values, err error) {
+	apiErr := &APIError{}
+	if c.rateLimiter == nil || path == "/events/get" || !errors.As(err, &apiErr) || !errors.Is(apiErr, ErrRateLimited) {
+		return
+	}
+
+	c.rateLimiter.Throttled(params.Get("chatId"), apiErr.RetryAfter)
+}
+
 func (c *Client) AutosubscribeToThreads(chatID string, enable, withExisting bool) error {
+	return c.AutosubscribeToThreadsWithContext(context.Background(), chatID, enable, withExisting)
+}
+
+func (c *Client) AutosubscribeToThreadsWithContext(ctx context.Context, chatID string, enable, withExisting bool) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -125,7 +329,7 @@ func (c *Client) AutosubscribeToThreads(chatID string, enable, withExisting bool
 		"withExisting": {strconv.FormatBool(withExisting)},
 	}
 
-	if _, err := c.Do("/threads/autosubscribe", params, nil); err != nil {
+	if _, err := c.DoWithContext(ctx, "/threads/autosubscribe", params, nil); err != nil {
 		return fmt.Errorf("error while requesting threads autosubscribe: %w", err)
 	}
 
@@ -133,6 +337,10 @@ func (c *Client) AutosubscribeToThreads(chatID string, enable, withExisting bool
 }
 
 func (c *Client) AddThread(chatID, msgID string) (*Thread, error) {
+	return c.AddThreadWithContext(context.Background(), chatID, msgID)
+}
+
+func (c *Client) AddThreadWithContext(ctx context.Context, chatID, msgID string) (*Thread, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -145,7 +353,7 @@ func (c *Client) AddThread(chatID, msgID string) (*Thread, error) {
 		"msgId":  {msgID},
 	}
 
-	response, err := c.Do("/threads/add", params, nil)
+	response, err := c.DoWithContext(ctx, "/threads/add", params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error while adding thread: %w", err)
 	}
@@ -159,6 +367,10 @@ func (c *Client) AddThread(chatID, msgID string) (*Thread, error) {
 }
 
 func (c *Client) GetThreadSubscribers(threadID string, cursor string, pageSize int) (*ThreadSubscribers, error) {
+	return c.GetThreadSubscribersWithContext(context.Background(), threadID, cursor, pageSize)
+}
+
+func (c *Client) GetThreadSubscribersWithContext(ctx context.Context, threadID string, cursor string, pageSize int) (*ThreadSubscribers, error) {
 	if threadID == "" {
 		return nil, fmt.Errorf("threadID cannot be empty")
 	}
@@ -174,7 +386,7 @@ func (c *Client) GetThreadSubscribers(threadID string, cursor string, pageSize i
 		params.Set("pageSize", strconv.Itoa(pageSize))
 	}
 
-	response, err := c.Do("/threads/subscribers/get", params, nil)
+	response, err := c.DoWithContext(ctx, "/threads/subscribers/get", params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error while getting thread subscribers: %w", err)
 	}
@@ -188,20 +400,28 @@ func (c *Client) GetThreadSubscribers(threadID string, cursor string, pageSize i
 }
 
 func (c *Client) GetInfo() (*BotInfo, error) {
-	response, err := c.Do("/self/get", url.Values{}, nil)
+	return c.GetInfoWithContext(context.Background())
+}
+
+func (c *Client) GetInfoWithContext(ctx context.Context) (*BotInfo, error) {
+	response, err := c.DoWithContext(ctx, "/self/get", url.Values{}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving information: %s", err)
+		return nil, fmt.Errorf("error while receiving information: %w", err)
 	}
 
 	info := &BotInfo{}
 	if err := json.Unmarshal(response, info); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling information: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling information: %w", err)
 	}
 
 	return info, nil
 }
 
 func (c *Client) GetChatInfo(chatID string) (*Chat, error) {
+	return c.GetChatInfoWithContext(context.Background(), chatID)
+}
+
+func (c *Client) GetChatInfoWithContext(ctx context.Context, chatID string) (*Chat, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -209,9 +429,9 @@ func (c *Client) GetChatInfo(chatID string) (*Chat, error) {
 	params := url.Values{
 		"chatId": {chatID},
 	}
-	response, err := c.Do("/chats/getInfo", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/getInfo", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving information: %s", err)
+		return nil, fmt.Errorf("error while receiving information: %w", err)
 	}
 
 	chat := &Chat{
@@ -219,7 +439,7 @@ func (c *Client) GetChatInfo(chatID string) (*Chat, error) {
 		ID:     chatID,
 	}
 	if err := json.Unmarshal(response, chat); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling information: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling information: %w", err)
 	}
 
 	if chat.Type == Private {
@@ -229,6 +449,10 @@ func (c *Client) GetChatInfo(chatID string) (*Chat, error) {
 }
 
 func (c *Client) SendChatActions(chatID string, actions ...ChatAction) error {
+	return c.SendChatActionsWithContext(context.Background(), chatID, actions...)
+}
+
+func (c *Client) SendChatActionsWithContext(ctx context.Context, chatID string, actions ...ChatAction) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -248,14 +472,18 @@ func (c *Client) SendChatActions(chatID string, actions ...ChatAction) error {
 		"chatId":  {chatID},
 		"actions": filteredActions,
 	}
-	_, err := c.Do("/chats/sendActions", params, nil)
+	_, err := c.DoWithContext(ctx, "/chats/sendActions", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while receiving information: %s", err)
+		return fmt.Errorf("error while receiving information: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) GetChatAdmins(chatID string) ([]ChatMember, error) {
+	return c.GetChatAdminsWithContext(context.Background(), chatID)
+}
+
+func (c *Client) GetChatAdminsWithContext(ctx context.Context, chatID string) ([]ChatMember, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -264,19 +492,23 @@ func (c *Client) GetChatAdmins(chatID string) ([]ChatMember, error) {
 		"chatId": {chatID},
 	}
 
-	response, err := c.Do("/chats/getAdmins", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/getAdmins", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving admins: %s", err)
+		return nil, fmt.Errorf("error while receiving admins: %w", err)
 	}
 
 	admins := new(AdminsListResponse)
 	if err := json.Unmarshal(response, admins); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling admins: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling admins: %w", err)
 	}
 	return admins.List, nil
 }
 
 func (c *Client) GetChatMembers(chatID string) ([]ChatMember, error) {
+	return c.GetChatMembersWithContext(context.Background(), chatID)
+}
+
+func (c *Client) GetChatMembersWithContext(ctx context.Context, chatID string) ([]ChatMember, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -285,19 +517,23 @@ func (c *Client) GetChatMembers(chatID string) ([]ChatMember, error) {
 		"chatId": {chatID},
 	}
 
-	response, err := c.Do("/chats/getMembers", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/getMembers", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving members: %s", err)
+		return nil, fmt.Errorf("error while receiving members: %w", err)
 	}
 
 	members := new(MembersListResponse)
 	if err := json.Unmarshal(response, members); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling members: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling members: %w", err)
 	}
 	return members.List, nil
 }
 
 func (c *Client) GetChatBlockedUsers(chatID string) ([]User, error) {
+	return c.GetChatBlockedUsersWithContext(context.Background(), chatID)
+}
+
+func (c *Client) GetChatBlockedUsersWithContext(ctx context.Context, chatID string) ([]User, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -306,19 +542,23 @@ func (c *Client) GetChatBlockedUsers(chatID string) ([]User, error) {
 		"chatId": {chatID},
 	}
 
-	response, err := c.Do("/chats/getBlockedUsers", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/getBlockedUsers", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving blocked users: %s", err)
+		return nil, fmt.Errorf("error while receiving blocked users: %w", err)
 	}
 
 	users := new(UsersListResponse)
 	if err := json.Unmarshal(response, users); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling blocked users: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling blocked users: %w", err)
 	}
 	return users.List, nil
 }
 
 func (c *Client) GetChatPendingUsers(chatID string) ([]User, error) {
+	return c.GetChatPendingUsersWithContext(context.Background(), chatID)
+}
+
+func (c *Client) GetChatPendingUsersWithContext(ctx context.Context, chatID string) ([]User, error) {
 	if chatID == "" {
 		return nil, fmt.Errorf("chatID cannot be empty")
 	}
@@ -327,19 +567,23 @@ func (c *Client) GetChatPendingUsers(chatID string) ([]User, error) {
 		"chatId": {chatID},
 	}
 
-	response, err := c.Do("/chats/getPendingUsers", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/getPendingUsers", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving pending users: %s", err)
+		return nil, fmt.Errorf("error while receiving pending users: %w", err)
 	}
 
 	users := new(UsersListResponse)
 	if err := json.Unmarshal(response, users); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling pending users: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling pending users: %w", err)
 	}
 	return users.List, nil
 }
 
 func (c *Client) BlockChatUser(chatID, userID string, deleteLastMessages bool) error {
+	return c.BlockChatUserWithContext(context.Background(), chatID, userID, deleteLastMessages)
+}
+
+func (c *Client) BlockChatUserWithContext(ctx context.Context, chatID, userID string, deleteLastMessages bool) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -353,19 +597,23 @@ func (c *Client) BlockChatUser(chatID, userID string, deleteLastMessages bool) e
 		"delLastMessages": {strconv.FormatBool(deleteLastMessages)},
 	}
 
-	response, err := c.Do("/chats/blockUser", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/blockUser", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while blocking user: %s", err)
+		return fmt.Errorf("error while blocking user: %w", err)
 	}
 
 	users := new(UsersListResponse)
 	if err := json.Unmarshal(response, users); err != nil {
-		return fmt.Errorf("error while blocking user: %s", err)
+		return fmt.Errorf("error while blocking user: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) UnblockChatUser(chatID, userID string) error {
+	return c.UnblockChatUserWithContext(context.Background(), chatID, userID)
+}
+
+func (c *Client) UnblockChatUserWithContext(ctx context.Context, chatID, userID string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -378,19 +626,23 @@ func (c *Client) UnblockChatUser(chatID, userID string) error {
 		"userId": {userID},
 	}
 
-	response, err := c.Do("/chats/unblockUser", params, nil)
+	response, err := c.DoWithContext(ctx, "/chats/unblockUser", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while unblocking user: %s", err)
+		return fmt.Errorf("error while unblocking user: %w", err)
 	}
 
 	users := new(UsersListResponse)
 	if err := json.Unmarshal(response, users); err != nil {
-		return fmt.Errorf("error while unblocking user: %s", err)
+		return fmt.Errorf("error while unblocking user: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) ResolveChatPending(chatID, userID string, approve, everyone bool) error {
+	return c.ResolveChatPendingWithContext(context.Background(), chatID, userID, approve, everyone)
+}
+
+func (c *Client) ResolveChatPendingWithContext(ctx context.Context, chatID, userID string, approve, everyone bool) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -405,13 +657,17 @@ func (c *Client) ResolveChatPending(chatID, userID string, approve, everyone boo
 		params.Set("userId", userID)
 	}
 
-	if _, err := c.Do("/chats/resolvePending", params, nil); err != nil {
-		return fmt.Errorf("error while resolving chat pendings: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/resolvePending", params, nil); err != nil {
+		return fmt.Errorf("error while resolving chat pendings: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) DeleteChatMembers(chatID string, members []string) error {
+	return c.DeleteChatMembersWithContext(context.Background(), chatID, members)
+}
+
+func (c *Client) DeleteChatMembersWithContext(ctx context.Context, chatID string, members []string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -426,7 +682,7 @@ func (c *Client) DeleteChatMembers(chatID string, members []string) error {
 
 	membersJSON, err := json.Marshal(membersList)
 	if err != nil {
-		return fmt.Errorf("error while marshalling members list: %s", err)
+		return fmt.Errorf("error while marshalling members list: %w", err)
 	}
 
 	params := url.Values{
@@ -434,13 +690,17 @@ func (c *Client) DeleteChatMembers(chatID string, members []string) error {
 		"members": {string(membersJSON)},
 	}
 
-	if _, err := c.Do("/chats/members/delete", params, nil); err != nil {
-		return fmt.Errorf("error while deleting chat members: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/members/delete", params, nil); err != nil {
+		return fmt.Errorf("error while deleting chat members: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) AddChatMembers(chatID string, members []string) error {
+	return c.AddChatMembersWithContext(context.Background(), chatID, members)
+}
+
+func (c *Client) AddChatMembersWithContext(ctx context.Context, chatID string, members []string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -455,7 +715,7 @@ func (c *Client) AddChatMembers(chatID string, members []string) error {
 
 	membersJSON, err := json.Marshal(membersList)
 	if err != nil {
-		return fmt.Errorf("error while marshalling members list: %s", err)
+		return fmt.Errorf("error while marshalling members list: %w", err)
 	}
 
 	params := url.Values{
@@ -463,13 +723,17 @@ func (c *Client) AddChatMembers(chatID string, members []string) error {
 		"members": {string(membersJSON)},
 	}
 
-	if _, err := c.Do("/chats/members/add", params, nil); err != nil {
-		return fmt.Errorf("error while adding chat members: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/members/add", params, nil); err != nil {
+		return fmt.Errorf("error while adding chat members: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) SetChatTitle(chatID, title string) error {
+	return c.SetChatTitleWithContext(context.Background(), chatID, title)
+}
+
+func (c *Client) SetChatTitleWithContext(ctx context.Context, chatID, title string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -482,13 +746,17 @@ func (c *Client) SetChatTitle(chatID, title string) error {
 		"title":  {title},
 	}
 
-	if _, err := c.Do("/chats/setTitle", params, nil); err != nil {
-		return fmt.Errorf("error while setting chat title: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/setTitle", params, nil); err != nil {
+		return fmt.Errorf("error while setting chat title: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) SetChatAbout(chatID, about string) error {
+	return c.SetChatAboutWithContext(context.Background(), chatID, about)
+}
+
+func (c *Client) SetChatAboutWithContext(ctx context.Context, chatID, about string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -498,13 +766,17 @@ func (c *Client) SetChatAbout(chatID, about string) error {
 		"about":  {about},
 	}
 
-	if _, err := c.Do("/chats/setAbout", params, nil); err != nil {
-		return fmt.Errorf("error while setting chat about: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/setAbout", params, nil); err != nil {
+		return fmt.Errorf("error while setting chat about: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) SetChatRules(chatID, rules string) error {
+	return c.SetChatRulesWithContext(context.Background(), chatID, rules)
+}
+
+func (c *Client) SetChatRulesWithContext(ctx context.Context, chatID, rules string) error {
 	if chatID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
@@ -514,13 +786,17 @@ func (c *Client) SetChatRules(chatID, rules string) error {
 		"rules":  {rules},
 	}
 
-	if _, err := c.Do("/chats/setRules", params, nil); err != nil {
-		return fmt.Errorf("error while setting chat rules: %s", err)
+	if _, err := c.DoWithContext(ctx, "/chats/setRules", params, nil); err != nil {
+		return fmt.Errorf("error while setting chat rules: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) GetFileInfo(fileID string) (*File, error) {
+	return c.GetFileInfoWithContext(context.Background(), fileID)
+}
+
+func (c *Client) GetFileInfoWithContext(ctx context.Context, fileID string) (*File, error) {
 	if fileID == "" {
 		return nil, fmt.Errorf("fileID cannot be empty")
 	}
@@ -528,24 +804,32 @@ func (c *Client) GetFileInfo(fileID string) (*File, error) {
 	params := url.Values{
 		"fileId": {fileID},
 	}
-	response, err := c.Do("/files/getInfo", params, nil)
+	response, err := c.DoWithContext(ctx, "/files/getInfo", params, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while receiving information: %s", err)
+		return nil, fmt.Errorf("error while receiving information: %w", err)
 	}
 
 	file := &File{}
 	if err := json.Unmarshal(response, file); err != nil {
-		return nil, fmt.Errorf("error while unmarshalling information: %s", err)
+		return nil, fmt.Errorf("error while unmarshalling information: %w", err)
 	}
 
 	return file, nil
 }
 
 func (c *Client) GetVoiceInfo(fileID string) (*File, error) {
-	return c.GetFileInfo(fileID)
+	return c.GetVoiceInfoWithContext(context.Background(), fileID)
+}
+
+func (c *Client) GetVoiceInfoWithContext(ctx context.Context, fileID string) (*File, error) {
+	return c.GetFileInfoWithContext(ctx, fileID)
 }
 
 func (c *Client) SendTextMessage(message *Message) error {
+	return c.SendTextMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) SendTextMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -574,7 +858,7 @@ func (c *Client) SendTextMessage(message *Message) error {
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
@@ -584,19 +868,23 @@ func (c *Client) SendTextMessage(message *Message) error {
 		params.Set("parseMode", string(message.ParseMode))
 	}
 
-	response, err := c.Do("/messages/sendText", params, nil)
+	response, err := c.DoWithContext(ctx, "/messages/sendText", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while sending text: %s", err)
+		return fmt.Errorf("error while sending text: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response from API: %s", err)
+		return fmt.Errorf("cannot unmarshal response from API: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) SendTextWithDeeplinkMessage(message *Message) error {
+	return c.SendTextWithDeeplinkMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) SendTextWithDeeplinkMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -625,7 +913,7 @@ func (c *Client) SendTextWithDeeplinkMessage(message *Message) error {
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
@@ -640,19 +928,23 @@ func (c *Client) SendTextWithDeeplinkMessage(message *Message) error {
 		params.Set("parseMode", string(message.ParseMode))
 	}
 
-	response, err := c.Do("/messages/sendTextWithDeeplink", params, nil)
+	response, err := c.DoWithContext(ctx, "/messages/sendTextWithDeeplink", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while sending text: %s", err)
+		return fmt.Errorf("error while sending text: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response from API: %s", err)
+		return fmt.Errorf("cannot unmarshal response from API: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) EditMessage(message *Message) error {
+	return c.EditMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) EditMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -667,15 +959,16 @@ func (c *Client) EditMessage(message *Message) error {
 	}
 
 	params := url.Values{
-		"msgId":  {message.ID},
-		"chatId": {message.Chat.ID},
-		"text":   {message.Text},
+		"msgId":      {message.ID},
+		"chatId":     {message.Chat.ID},
+		"text":       {message.Text},
+		"request-id": {message.RequestID},
 	}
 
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
@@ -685,19 +978,23 @@ func (c *Client) EditMessage(message *Message) error {
 		params.Set("parseMode", string(message.ParseMode))
 	}
 
-	response, err := c.Do("/messages/editText", params, nil)
+	response, err := c.DoWithContext(ctx, "/messages/editText", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while editing text: %s", err)
+		return fmt.Errorf("error while editing text: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response from API: %s", err)
+		return fmt.Errorf("cannot unmarshal response from API: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) DeleteMessage(message *Message) error {
+	return c.DeleteMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) DeleteMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -712,15 +1009,19 @@ func (c *Client) DeleteMessage(message *Message) error {
 		"msgId":  {message.ID},
 		"chatId": {message.Chat.ID},
 	}
-	_, err := c.Do("/messages/deleteMessages", params, nil)
+	_, err := c.DoWithContext(ctx, "/messages/deleteMessages", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while deleting message: %s", err)
+		return fmt.Errorf("error while deleting message: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) SendFileMessage(message *Message) error {
+	return c.SendFileMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) SendFileMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -732,9 +1033,10 @@ func (c *Client) SendFileMessage(message *Message) error {
 	}
 
 	params := url.Values{
-		"chatId":  {message.Chat.ID},
-		"caption": {message.Text},
-		"fileId":  {message.FileID},
+		"chatId":     {message.Chat.ID},
+		"caption":    {message.Text},
+		"fileId":     {message.FileID},
+		"request-id": {message.RequestID},
 	}
 
 	if message.ReplyMsgID != "" {
@@ -749,7 +1051,7 @@ func (c *Client) SendFileMessage(message *Message) error {
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
@@ -759,19 +1061,23 @@ func (c *Client) SendFileMessage(message *Message) error {
 		params.Set("parseMode", string(message.ParseMode))
 	}
 
-	response, err := c.Do("/messages/sendFile", params, nil)
+	response, err := c.DoWithContext(ctx, "/messages/sendFile", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while making request: %s", err)
+		return fmt.Errorf("error while making request: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response: %s", err)
+		return fmt.Errorf("cannot unmarshal response: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) SendVoiceMessage(message *Message) error {
+	return c.SendVoiceMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) SendVoiceMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -783,9 +1089,10 @@ func (c *Client) SendVoiceMessage(message *Message) error {
 	}
 
 	params := url.Values{
-		"chatId":  {message.Chat.ID},
-		"caption": {message.Text},
-		"fileId":  {message.FileID},
+		"chatId":     {message.Chat.ID},
+		"caption":    {message.Text},
+		"fileId":     {message.FileID},
+		"request-id": {message.RequestID},
 	}
 
 	if message.ReplyMsgID != "" {
@@ -800,93 +1107,103 @@ func (c *Client) SendVoiceMessage(message *Message) error {
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
 	}
 
-	response, err := c.Do("/messages/sendVoice", params, nil)
+	response, err := c.DoWithContext(ctx, "/messages/sendVoice", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while making request: %s", err)
+		return fmt.Errorf("error while making request: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response: %s", err)
+		return fmt.Errorf("cannot unmarshal response: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) UploadFile(message *Message) error {
+	return c.UploadFileWithContext(context.Background(), message)
+}
+
+func (c *Client) UploadFileWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
 	if message.Chat.ID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
-	if message.File == nil {
+	if !message.hasNewFile() {
 		return fmt.Errorf("file cannot be nil")
 	}
 
 	params := url.Values{
-		"chatId":  {message.Chat.ID},
-		"caption": {message.Text},
+		"chatId":     {message.Chat.ID},
+		"caption":    {message.Text},
+		"request-id": {message.RequestID},
 	}
 
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
 	}
 
-	response, err := c.Do("/messages/sendFile", params, message.File)
+	response, err := c.call(ctx, "/messages/sendFile", params, message.fileUpload())
 	if err != nil {
-		return fmt.Errorf("error while making request: %s", err)
+		return fmt.Errorf("error while making request: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response: %s", err)
+		return fmt.Errorf("cannot unmarshal response: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) UploadVoice(message *Message) error {
+	return c.UploadVoiceWithContext(context.Background(), message)
+}
+
+func (c *Client) UploadVoiceWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
 	if message.Chat.ID == "" {
 		return fmt.Errorf("chatID cannot be empty")
 	}
-	if message.File == nil {
+	if !message.hasNewFile() {
 		return fmt.Errorf("file cannot be nil")
 	}
 
 	params := url.Values{
-		"chatId":  {message.Chat.ID},
-		"caption": {message.Text},
+		"chatId":     {message.Chat.ID},
+		"caption":    {message.Text},
+		"request-id": {message.RequestID},
 	}
 
 	if message.InlineKeyboard != nil {
 		data, err := json.Marshal(message.InlineKeyboard.GetKeyboard())
 		if err != nil {
-			return fmt.Errorf("cannot marshal inline keyboard markup: %s", err)
+			return fmt.Errorf("cannot marshal inline keyboard markup: %w", err)
 		}
 
 		params.Set("inlineKeyboardMarkup", string(data))
 	}
 
-	response, err := c.Do("/messages/sendVoice", params, message.File)
+	response, err := c.call(ctx, "/messages/sendVoice", params, message.fileUpload())
 	if err != nil {
-		return fmt.Errorf("error while making request: %s", err)
+		return fmt.Errorf("error while making request: %w", err)
 	}
 
 	if err := json.Unmarshal(response, message); err != nil {
-		return fmt.Errorf("cannot unmarshal response: %s", err)
+		return fmt.Errorf("cannot unmarshal response: %w", err)
 	}
 
 	return nil
@@ -905,17 +1222,21 @@ func (c *Client) GetEventsWithContext(ctx context.Context, lastEventID int, poll
 
 	response, err := c.DoWithContext(ctx, "/events/get", params, nil)
 	if err != nil {
-		return events.Events, fmt.Errorf("error while making request: %s", err)
+		return events.Events, fmt.Errorf("error while making request: %w", err)
 	}
 
 	if err := json.Unmarshal(response, events); err != nil {
-		return events.Events, fmt.Errorf("cannot parse events: %s", err)
+		return events.Events, fmt.Errorf("cannot parse events: %w", err)
 	}
 
 	return events.Events, nil
 }
 
 func (c *Client) PinMessage(message *Message) error {
+	return c.PinMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) PinMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -930,15 +1251,19 @@ func (c *Client) PinMessage(message *Message) error {
 		"chatId": {message.Chat.ID},
 		"msgId":  {message.ID},
 	}
-	_, err := c.Do("/chats/pinMessage", params, nil)
+	_, err := c.DoWithContext(ctx, "/chats/pinMessage", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while pinning message: %s", err)
+		return fmt.Errorf("error while pinning message: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) UnpinMessage(message *Message) error {
+	return c.UnpinMessageWithContext(context.Background(), message)
+}
+
+func (c *Client) UnpinMessageWithContext(ctx context.Context, message *Message) error {
 	if message == nil {
 		return fmt.Errorf("message cannot be nil")
 	}
@@ -953,15 +1278,19 @@ func (c *Client) UnpinMessage(message *Message) error {
 		"chatId": {message.Chat.ID},
 		"msgId":  {message.ID},
 	}
-	_, err := c.Do("/chats/unpinMessage", params, nil)
+	_, err := c.DoWithContext(ctx, "/chats/unpinMessage", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while unpinning message: %s", err)
+		return fmt.Errorf("error while unpinning message: %w", err)
 	}
 
 	return nil
 }
 
 func (c *Client) SendAnswerCallbackQuery(answer *ButtonResponse) error {
+	return c.SendAnswerCallbackQueryWithContext(context.Background(), answer)
+}
+
+func (c *Client) SendAnswerCallbackQueryWithContext(ctx context.Context, answer *ButtonResponse) error {
 	if answer == nil {
 		return fmt.Errorf("answer cannot be nil")
 	}
@@ -976,9 +1305,9 @@ func (c *Client) SendAnswerCallbackQuery(answer *ButtonResponse) error {
 		"showAlert": {strconv.FormatBool(answer.ShowAlert)},
 	}
 
-	_, err := c.Do("/messages/answerCallbackQuery", params, nil)
+	_, err := c.DoWithContext(ctx, "/messages/answerCallbackQuery", params, nil)
 	if err != nil {
-		return fmt.Errorf("error while making request: %s", err)
+		return fmt.Errorf("error while making request: %w", err)
 	}
 
 	return nil
@@ -989,6 +1318,10 @@ func NewClient(baseURL string, token string, logger *logrus.Logger) *Client {
 }
 
 func NewCustomClient(client *http.Client, baseURL string, token string, logger *logrus.Logger) *Client {
+	return newClient(client, baseURL, token, NewLogrusLogger(logger))
+}
+
+func newClient(client *http.Client, baseURL string, token string, logger Logger) *Client {
 	return &Client{
 		token:   token,
 		baseURL: baseURL,
@@ -1,9 +1,13 @@
 package botgolang
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -20,7 +24,7 @@ func TestClient_Do_OK(t *testing.T) {
 		baseURL: testServer.URL,
 		token:   "test_token",
 		client:  http.DefaultClient,
-		logger:  &logrus.Logger{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
 	}
 
 	bytes, err := client.Do("/", url.Values{}, nil)
@@ -38,7 +42,7 @@ func TestClient_Do_Error(t *testing.T) {
 		baseURL: testServer.URL,
 		token:   "",
 		client:  http.DefaultClient,
-		logger:  &logrus.Logger{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
 	}
 
 	expected := `{"ok":false, "description":"Missing required parameter 'token'"}`
@@ -49,6 +53,197 @@ func TestClient_Do_Error(t *testing.T) {
 	assert.JSONEq(expected, string(bytes))
 }
 
+func TestClient_Do_APIError(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+
+	err := client.SendTextMessage(&Message{
+		Chat:      Chat{ID: "id_1234"},
+		Text:      "text",
+		RequestID: "req_1",
+	})
+
+	require.Error(err)
+	assert.ErrorIs(err, ErrInvalidToken)
+	assert.NotErrorIs(err, ErrTransport)
+
+	apiErr := &APIError{}
+	require.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusOK, apiErr.StatusCode)
+	assert.Equal("/messages/sendText", apiErr.Path)
+	assert.Equal("req_1", apiErr.RequestID)
+	assert.Equal("Missing required parameter 'token'", apiErr.Description)
+}
+
+func TestClient_Do_HTTPStatusError(t *testing.T) {
+	assert := assert.New(t)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+
+	_, err := client.Do("/", url.Values{}, nil)
+
+	assert.ErrorIs(err, ErrRateLimited)
+	assert.EqualError(err, "error status from API: Too Many Requests")
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	tests := []struct {
+		description string
+		err         error
+	}{
+		{"Missing required parameter 'token'", ErrInvalidToken},
+		{"Invalid token", ErrInvalidToken},
+		{"Bot with this token is not a member of the chat", ErrNotChatMember},
+		{"Permission denied for the token", ErrPermissionDenied},
+		{"Chat not found for this token", ErrNotFound},
+		{"Something went wrong", ErrUnknownAPIError},
+	}
+
+	for _, test := range tests {
+		assert.ErrorIs(t, classifyAPIError(http.StatusOK, test.description), test.err, test.description)
+	}
+}
+
+func TestClient_Do_TransportError(t *testing.T) {
+	assert := assert.New(t)
+	testServer := httptest.NewServer(&MockHandler{})
+	testServer.Close()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+
+	_, err := client.GetInfo()
+
+	assert.ErrorIs(err, ErrTransport)
+	assert.False(errors.As(err, new(*APIError)))
+}
+
+func TestClient_Do_PostForm(t *testing.T) {
+	tests := []struct {
+		name          string
+		postForm      bool
+		postThreshold int
+		text          string
+		wantMethod    string
+	}{
+		{"default", false, 0, "short", http.MethodGet},
+		{"always", true, 0, "short", http.MethodPost},
+		{"below_threshold", false, 100, "short", http.MethodGet},
+		{"above_threshold", false, 100, strings.Repeat("long", 100), http.MethodPost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, query string
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method, query = r.Method, r.URL.RawQuery
+				(&MockHandler{}).ServeHTTP(w, r)
+			}))
+			defer func() { testServer.Close() }()
+
+			client := Client{
+				baseURL:       testServer.URL,
+				token:         "test_token",
+				client:        http.DefaultClient,
+				logger:        NewLogrusLogger(&logrus.Logger{}),
+				postForm:      tt.postForm,
+				postThreshold: tt.postThreshold,
+			}
+
+			err := client.SendTextMessage(&Message{Chat: Chat{ID: "id_1234"}, Text: tt.text})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMethod, method)
+			assert.Equal(t, tt.wantMethod == http.MethodGet, strings.Contains(query, "token=test_token"))
+		})
+	}
+}
+
+func TestClient_UploadFile_Reader(t *testing.T) {
+	tests := []struct {
+		name       string
+		reader     io.Reader
+		wantLength bool
+	}{
+		{"sized", strings.NewReader("report data"), true},
+		{"stream", io.MultiReader(strings.NewReader("report "), strings.NewReader("data")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fileName, content string
+			var contentLength int64
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentLength = r.ContentLength
+				file, header, err := r.FormFile("file")
+				if assert.NoError(t, err) {
+					data, _ := io.ReadAll(file)
+					fileName, content = header.Filename, string(data)
+				}
+				(&MockHandler{}).ServeHTTP(w, r)
+			}))
+			defer func() { testServer.Close() }()
+
+			client := Client{
+				baseURL: testServer.URL,
+				token:   "test_token",
+				client:  http.DefaultClient,
+				logger:  NewLogrusLogger(&logrus.Logger{}),
+			}
+
+			message := &Message{client: &client, Chat: Chat{ID: "id_1234"}}
+			message.AttachNewReader("report.txt", tt.reader)
+
+			require.NoError(t, message.Send())
+			assert.Equal(t, "report.txt", fileName)
+			assert.Equal(t, "report data", content)
+			assert.Equal(t, tt.wantLength, contentLength > 0)
+		})
+	}
+}
+
+func TestClient_WithContext_Canceled(t *testing.T) {
+	assert := assert.New(t)
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetChatInfoWithContext(ctx, "id_1234")
+	assert.ErrorIs(err, context.Canceled)
+	assert.ErrorIs(err, ErrTransport)
+
+	message := &Message{client: &client, Chat: Chat{ID: "id_1234"}, Text: "text"}
+	assert.ErrorIs(message.SendWithContext(ctx), context.Canceled)
+}
+
 func TestClient_GetEvents_OK(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -111,6 +306,10 @@ func TestClient_GetEvents_OK(t *testing.T) {
 								MsgID: "12354",
 								Text:  "test1",
 							},
+							Message: PartMessage{
+								MsgID: "12354",
+								Text:  "test1",
+							},
 						},
 					},
 					{
@@ -120,6 +319,10 @@ func TestClient_GetEvents_OK(t *testing.T) {
 								MsgID: "12354",
 								Text:  "test",
 							},
+							Message: PartMessage{
+								MsgID: "12354",
+								Text:  "test",
+							},
 						},
 					},
 				},
@@ -144,6 +347,7 @@ func TestClient_GetEvents_OK(t *testing.T) {
 					Text:      "Hello!",
 					Timestamp: 1546290000,
 				},
+				EditedTimestamp: 1546290099,
 			},
 		},
 		{
@@ -280,12 +484,19 @@ func TestClient_GetEvents_OK(t *testing.T) {
 		baseURL: testServer.URL,
 		token:   "test_token",
 		client:  http.DefaultClient,
-		logger:  &logrus.Logger{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
 	}
 
 	events, err := client.GetEvents(0, 0)
 
 	require.NoError(err)
+	// the raw JSON is checked in TestEvent_RawJSON
+	for _, event := range events {
+		event.raw, event.Payload.raw = nil, nil
+		for i := range event.Payload.Parts {
+			event.Payload.Parts[i].raw = nil
+		}
+	}
 	assert.Equal(events, expected)
 }
 
@@ -299,7 +510,7 @@ func TestClient_GetInfo_OK(t *testing.T) {
 		baseURL: testServer.URL,
 		token:   "test_token",
 		client:  http.DefaultClient,
-		logger:  &logrus.Logger{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
 	}
 
 	info, err := client.GetChatInfo("id_1234")
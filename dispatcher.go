@@ -0,0 +1,201 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultDispatcherWorkers   = 8
+	defaultDispatcherQueueSize = 100
+)
+
+// ErrDispatcherClosed is returned by Dispatcher.Dispatch after Close
+var ErrDispatcherClosed = errors.New("dispatcher is closed")
+
+// DispatcherConfig defines the concurrency of the dispatcher
+type DispatcherConfig struct {
+	// Number of workers handling events in parallel, 8 by default
+	Workers int
+
+	// Number of events waiting for every worker, 100 by default.
+	// Dispatch blocks while the queue of the worker is full.
+	QueueSize int
+
+	// Metrics to export the dispatcher statistics to, optional
+	Metrics *Metrics
+}
+
+func (c DispatcherConfig) withDefaults() DispatcherConfig {
+	if c.Workers <= 0 {
+		c.Workers = defaultDispatcherWorkers
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultDispatcherQueueSize
+	}
+	return c
+}
+
+// DispatcherStats is the snapshot of the dispatcher state
+type DispatcherStats struct {
+	// Number of workers
+	Workers int
+
+	// Number of events waiting in the queues
+	Queued int
+
+	// Length of the longest queue, a hot chat makes it much longer than the average
+	MaxQueueLength int
+
+	// Number of events being handled now
+	InFlight int
+
+	// Number of handled events
+	Handled uint64
+
+	// Number of times Dispatch waited for the full queue
+	Blocked uint64
+}
+
+// Dispatcher handles events by a pool of workers. Events are sharded by the chat id,
+// so events of one chat, including callback queries of its buttons, are handled sequentially
+// in the order of delivery while different chats are handled in parallel.
+type Dispatcher struct {
+	handler func(Event)
+	metrics *Metrics
+	queues  []chan Event
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	inFlight int64
+	handled  uint64
+	blocked  uint64
+}
+
+// NewDispatcher starts the workers calling handler for dispatched events
+func NewDispatcher(config DispatcherConfig, handler func(Event)) *Dispatcher {
+	config = config.withDefaults()
+
+	d := &Dispatcher{
+		handler: handler,
+		metrics: config.Metrics,
+		queues:  make([]chan Event, config.Workers),
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan Event, config.QueueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	if d.metrics != nil {
+		d.metrics.setDispatcher(d)
+	}
+
+	return d
+}
+
+// Dispatch queues the event to the worker of its chat. It waits while the queue is full
+// and returns ctx error if ctx is done earlier.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
+	queue := d.queues[d.shard(event.chat().ID)]
+	select {
+	case queue <- event:
+		return nil
+	default:
+	}
+
+	atomic.AddUint64(&d.blocked, 1)
+	select {
+	case queue <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run dispatches events from the channel until it is closed or ctx is done,
+// then closes the dispatcher and waits for queued events to be handled
+func (d *Dispatcher) Run(ctx context.Context, events <-chan Event) error {
+	defer d.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := d.Dispatch(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close stops accepting events and waits until the queued events are handled.
+// Close of a closed dispatcher only waits.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// Stats returns the snapshot of the dispatcher state, it is safe to call concurrently
+func (d *Dispatcher) Stats() DispatcherStats {
+	stats := DispatcherStats{
+		Workers:  len(d.queues),
+		InFlight: int(atomic.LoadInt64(&d.inFlight)),
+		Handled:  atomic.LoadUint64(&d.handled),
+		Blocked:  atomic.LoadUint64(&d.blocked),
+	}
+	for _, queue := range d.queues {
+		length := len(queue)
+		stats.Queued += length
+		if length > stats.MaxQueueLength {
+			stats.MaxQueueLength = length
+		}
+	}
+	return stats
+}
+
+func (d *Dispatcher) shard(chatID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(chatID))
+	return int(h.Sum32() % uint32(len(d.queues)))
+}
+
+func (d *Dispatcher) work(queue <-chan Event) {
+	defer d.wg.Done()
+
+	for event := range queue {
+		atomic.AddInt64(&d.inFlight, 1)
+		start := time.Now()
+		d.handler(event)
+		if d.metrics != nil {
+			d.metrics.observeHandling(time.Since(start))
+		}
+		atomic.AddInt64(&d.inFlight, -1)
+		atomic.AddUint64(&d.handled, 1)
+	}
+}
@@ -0,0 +1,168 @@
+package botgolang
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chatEvent(chatID string, eventID int) Event {
+	event := Event{EventID: eventID, Type: NEW_MESSAGE}
+	event.Payload.Chat.ID = chatID
+	return event
+}
+
+func TestDispatcher_Ordering(t *testing.T) {
+	assert := assert.New(t)
+
+	var mu sync.Mutex
+	handled := map[string][]int{}
+	slow := make(chan struct{})
+
+	dispatcher := NewDispatcher(DispatcherConfig{Workers: 4, QueueSize: 10}, func(event Event) {
+		if event.Payload.Chat.ID == "slow" {
+			<-slow
+		}
+		mu.Lock()
+		handled[event.Payload.Chat.ID] = append(handled[event.Payload.Chat.ID], event.EventID)
+		mu.Unlock()
+	})
+
+	ctx := context.Background()
+	require.NoError(t, dispatcher.Dispatch(ctx, chatEvent("slow", 0)))
+	var dispatched uint64
+	for i := 1; i <= 5; i++ {
+		for _, chatID := range []string{"a", "b", "c"} {
+			if dispatcher.shard(chatID) == dispatcher.shard("slow") {
+				continue
+			}
+			require.NoError(t, dispatcher.Dispatch(ctx, chatEvent(chatID, i)))
+			dispatched++
+		}
+	}
+	require.NotZero(t, dispatched)
+
+	assert.Eventually(func() bool {
+		stats := dispatcher.Stats()
+		return stats.Handled == dispatched && stats.InFlight == 1
+	}, time.Second, time.Millisecond, "other chats are blocked by the slow one")
+
+	close(slow)
+	dispatcher.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal([]int{0}, handled["slow"])
+	for chatID, eventIDs := range handled {
+		if chatID != "slow" {
+			assert.Equal([]int{1, 2, 3, 4, 5}, eventIDs, chatID)
+		}
+	}
+	assert.ErrorIs(dispatcher.Dispatch(ctx, chatEvent("a", 6)), ErrDispatcherClosed)
+}
+
+func callbackEvent(chatID string, eventID int) Event {
+	event := Event{EventID: eventID, Type: CALLBACK_QUERY}
+	event.Payload.CallbackMsg.Chat.ID = chatID
+	return event
+}
+
+func TestDispatcher_CallbackChat(t *testing.T) {
+	assert := assert.New(t)
+
+	var mu sync.Mutex
+	var handled []int
+	slow := make(chan struct{})
+
+	dispatcher := NewDispatcher(DispatcherConfig{Workers: 8}, func(event Event) {
+		if event.EventID == 0 {
+			<-slow
+		}
+		mu.Lock()
+		handled = append(handled, event.EventID)
+		mu.Unlock()
+	})
+
+	other := "b"
+	for dispatcher.shard(other) == dispatcher.shard("a") {
+		other += "b"
+	}
+
+	// the callback of the button in the busy chat waits for its message, the callback of other chat does not
+	ctx := context.Background()
+	require.NoError(t, dispatcher.Dispatch(ctx, chatEvent("a", 0)))
+	require.NoError(t, dispatcher.Dispatch(ctx, callbackEvent("a", 1)))
+	require.NoError(t, dispatcher.Dispatch(ctx, callbackEvent(other, 2)))
+
+	assert.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 1
+	}, time.Second, time.Millisecond)
+
+	close(slow)
+	dispatcher.Close()
+	assert.Equal([]int{2, 0, 1}, handled)
+}
+
+func TestDispatcher_BoundedQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	dispatcher := NewDispatcher(DispatcherConfig{Workers: 1, QueueSize: 1}, func(event Event) {
+		<-release
+	})
+
+	ctx := context.Background()
+	assert.NoError(dispatcher.Dispatch(ctx, chatEvent("a", 1)))
+	assert.Eventually(func() bool { return dispatcher.Stats().InFlight == 1 }, time.Second, time.Millisecond)
+	assert.NoError(dispatcher.Dispatch(ctx, chatEvent("a", 2)))
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(dispatcher.Dispatch(timeout, chatEvent("a", 3)), context.DeadlineExceeded)
+
+	stats := dispatcher.Stats()
+	assert.Equal(1, stats.Queued)
+	assert.Equal(1, stats.MaxQueueLength)
+	assert.Equal(uint64(1), stats.Blocked)
+
+	close(release)
+	dispatcher.Close()
+	assert.Equal(uint64(2), dispatcher.Stats().Handled)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	assert := assert.New(t)
+
+	metrics := NewMetrics(1)
+	var mu sync.Mutex
+	var handled int
+	dispatcher := NewDispatcher(DispatcherConfig{Metrics: metrics}, func(event Event) {
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+
+	events := make(chan Event, 20)
+	for i := 0; i < 20; i++ {
+		events <- chatEvent(fmt.Sprint(i%3), i)
+	}
+	close(events)
+
+	assert.NoError(dispatcher.Run(context.Background(), events))
+	assert.Equal(20, handled)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	assert.Contains(body, "botgolang_dispatcher_queued_events 0\n")
+	assert.Contains(body, "botgolang_dispatcher_in_flight_events 0\n")
+	assert.Contains(body, "botgolang_dispatcher_handling_duration_seconds_count 20\n")
+}
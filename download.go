@@ -0,0 +1,283 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+const (
+	// downloadPath identifies file downloads in retry policies and errors,
+	// the content itself is downloaded from File.URL
+	downloadPath = "/files/download"
+
+	partialFileSuffix = ".part"
+
+	// the sidecar file identifying the download the partial file belongs to
+	partialMetaSuffix = ".part.meta"
+)
+
+type downloadConfig struct {
+	maxSize int64
+}
+
+// DownloadOption configures DownloadFile and DownloadToPath
+type DownloadOption func(*downloadConfig)
+
+// DownloadMaxSize limits the size of the downloaded file.
+// ErrFileTooLarge is returned if the file is bigger, in this case nothing or only the allowed part is written.
+func DownloadMaxSize(size int64) DownloadOption {
+	return func(config *downloadConfig) {
+		config.maxSize = size
+	}
+}
+
+func (c *Client) DownloadFile(fileID string, w io.Writer, opts ...DownloadOption) (*File, error) {
+	return c.DownloadFileWithContext(context.Background(), fileID, w, opts...)
+}
+
+// DownloadFileWithContext writes the content of the file to w.
+// Interrupted downloads are resumed with range requests according to the retry policy of the client.
+func (c *Client) DownloadFileWithContext(ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption) (*File, error) {
+	info, err := c.GetFileInfoWithContext(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.download(ctx, info, w, 0, opts); err != nil {
+		return info, err
+	}
+
+	return info, nil
+}
+
+func (c *Client) DownloadToPath(fileID, path string, opts ...DownloadOption) (*File, error) {
+	return c.DownloadToPathWithContext(context.Background(), fileID, path, opts...)
+}
+
+// DownloadToPathWithContext saves the file to path.
+// The data is written to path.part first, so a download interrupted even between the calls
+// is continued from the last written byte and the file at path is always complete.
+// The id and the size of the file are kept in path.part.meta, the partial file of another file
+// is downloaded again from the start.
+func (c *Client) DownloadToPathWithContext(ctx context.Context, fileID, path string, opts ...DownloadOption) (*File, error) {
+	info, err := c.GetFileInfoWithContext(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	partialPath, metaPath := path+partialFileSuffix, path+partialMetaSuffix
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	meta := partialMeta(info)
+	if saved, err := os.ReadFile(metaPath); err != nil || string(saved) != meta {
+		// the partial file belongs to another download
+		flags |= os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(partialPath, flags, 0o644)
+	if err != nil {
+		return info, fmt.Errorf("cannot open file: %w", err)
+	}
+
+	if err := os.WriteFile(metaPath, []byte(meta), 0o644); err != nil {
+		_ = file.Close()
+		return info, fmt.Errorf("cannot write partial file meta: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return info, fmt.Errorf("cannot stat file: %w", err)
+	}
+
+	offset := stat.Size()
+	if info.Size > 0 && uint64(offset) > info.Size {
+		if err := file.Truncate(0); err != nil {
+			_ = file.Close()
+			return info, fmt.Errorf("cannot truncate file: %w", err)
+		}
+		offset = 0
+	}
+
+	if err := c.download(ctx, info, file, offset, opts); err != nil {
+		_ = file.Close()
+		return info, err
+	}
+
+	if err := file.Close(); err != nil {
+		return info, fmt.Errorf("cannot close file: %w", err)
+	}
+
+	if err := os.Rename(partialPath, path); err != nil {
+		return info, fmt.Errorf("cannot rename file: %w", err)
+	}
+
+	if err := os.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.logger.Warn("cannot remove partial file meta", LogFields{
+			"err": err,
+		})
+	}
+
+	return info, nil
+}
+
+// partialMeta identifies the file written to the partial file
+func partialMeta(info *File) string {
+	return info.ID + "\n" + strconv.FormatUint(info.Size, 10) + "\n"
+}
+
+// download writes the file content starting from offset to w and resumes it after transport errors
+func (c *Client) download(ctx context.Context, info *File, w io.Writer, offset int64, opts []DownloadOption) error {
+	config := &downloadConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if info.URL == "" {
+		return fmt.Errorf("file %s has no url", info.ID)
+	}
+
+	size := int64(info.Size)
+	if config.maxSize > 0 && size > config.maxSize {
+		return fmt.Errorf("%w: %d bytes, max %d", ErrFileTooLarge, size, config.maxSize)
+	}
+
+	for attempt := 1; ; attempt++ {
+		written, err := c.downloadRange(ctx, info, w, offset, config.maxSize)
+		offset += written
+		if err == nil {
+			break
+		}
+
+		if c.retryPolicy == nil || errors.Is(err, ErrFileTooLarge) {
+			return err
+		}
+
+		delay, retry := c.retryPolicy.NextRetry(attempt, downloadPath, err)
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+
+		c.logger.Warn("file download interrupted, resuming", LogFields{
+			"err":     err,
+			"file_id": info.ID,
+			"offset":  offset,
+			"attempt": attempt,
+			"delay":   delay,
+		})
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+
+	if size > 0 && offset != size {
+		return fmt.Errorf("%w: got %d bytes, expected %d", ErrFileSizeMismatch, offset, size)
+	}
+
+	return nil
+}
+
+// downloadRange requests the file from offset and returns the number of bytes written to w
+func (c *Client) downloadRange(ctx context.Context, info *File, w io.Writer, offset, maxSize int64) (int64, error) {
+	size := int64(info.Size)
+	if size > 0 && offset == size {
+		return 0, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.URL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot init http request: %w", err)
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	c.logger.Debug("downloading file", LogFields{
+		"file_id": info.ID,
+		"offset":  offset,
+	})
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrTransport, err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Error("cannot close body", LogFields{
+				"err": err,
+			})
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// the server ignored the range, skip the part we already have
+		if offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+				return 0, fmt.Errorf("%w: cannot skip downloaded part: %w", ErrTransport, err)
+			}
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the file of unknown size has been downloaded completely
+		if offset > 0 && size <= 0 {
+			return 0, nil
+		}
+		return 0, newAPIError(resp.StatusCode, "", downloadPath, "")
+	default:
+		return 0, newAPIError(resp.StatusCode, "", downloadPath, "")
+	}
+
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, maxSize-offset)
+	}
+
+	writer := &downloadWriter{writer: w}
+	written, err := io.Copy(writer, body)
+	if writer.err != nil {
+		return written, fmt.Errorf("cannot write file: %w", writer.err)
+	}
+
+	if err != nil {
+		return written, fmt.Errorf("%w: %w", ErrTransport, err)
+	}
+
+	if maxSize > 0 && offset+written == maxSize {
+		// check whether the file is bigger than allowed
+		if n, _ := io.ReadFull(resp.Body, make([]byte, 1)); n > 0 {
+			return written, fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, maxSize)
+		}
+	}
+
+	if size > 0 && offset+written < size {
+		return written, fmt.Errorf("%w: %w", ErrTransport, io.ErrUnexpectedEOF)
+	}
+
+	return written, nil
+}
+
+// downloadWriter keeps the error of the underlying writer to tell it apart from network errors
+type downloadWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
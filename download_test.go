@@ -0,0 +1,143 @@
+package botgolang
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const downloadContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newDownloadServer serves file info and the file content, the first content request is interrupted in the middle
+func newDownloadServer(t *testing.T, interrupt bool) (*httptest.Server, *int32) {
+	var contentCalls int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/getInfo":
+			_, _ = w.Write([]byte(`{"ok":true,"fileId":"` + r.FormValue("fileId") + `","type":"text","size":36,` +
+				`"filename":"data.txt","url":"` + server.URL + `/content"}`))
+		case "/content":
+			if atomic.AddInt32(&contentCalls, 1) == 1 && interrupt {
+				w.Header().Set("Content-Length", "36")
+				_, _ = w.Write([]byte(downloadContent[:10]))
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			}
+			http.ServeContent(w, r, "data.txt", time.Time{}, strings.NewReader(downloadContent))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &contentCalls
+}
+
+func newDownloadClient(url string, retryPolicy RetryPolicy) *Client {
+	return &Client{
+		baseURL:     url,
+		token:       "test_token",
+		client:      http.DefaultClient,
+		logger:      NewLogrusLogger(&logrus.Logger{}),
+		retryPolicy: retryPolicy,
+	}
+}
+
+func TestClient_DownloadFile_Resume(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	server, contentCalls := newDownloadServer(t, true)
+
+	client := newDownloadClient(server.URL, &ExponentialBackoff{MaxAttempts: 2, InitialInterval: time.Millisecond})
+
+	buffer := &bytes.Buffer{}
+	info, err := client.DownloadFile("file_1", buffer)
+	require.NoError(err)
+	assert.Equal("data.txt", info.Name)
+	assert.Equal(downloadContent, buffer.String())
+	assert.EqualValues(2, atomic.LoadInt32(contentCalls))
+}
+
+func TestClient_DownloadFile_NoRetry(t *testing.T) {
+	server, _ := newDownloadServer(t, true)
+
+	client := newDownloadClient(server.URL, nil)
+
+	_, err := client.DownloadFile("file_1", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrTransport)
+}
+
+func TestClient_DownloadFile_MaxSize(t *testing.T) {
+	server, contentCalls := newDownloadServer(t, false)
+
+	client := newDownloadClient(server.URL, nil)
+
+	buffer := &bytes.Buffer{}
+	_, err := client.DownloadFile("file_1", buffer, DownloadMaxSize(10))
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	assert.Zero(t, buffer.Len())
+	assert.Zero(t, atomic.LoadInt32(contentCalls))
+}
+
+func TestClient_DownloadToPath(t *testing.T) {
+	require := require.New(t)
+	server, _ := newDownloadServer(t, false)
+
+	client := newDownloadClient(server.URL, nil)
+
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(os.WriteFile(path+partialFileSuffix, []byte(downloadContent[:20]), 0o600))
+	require.NoError(os.WriteFile(path+partialMetaSuffix, []byte(partialMeta(&File{ID: "file_1", Size: 36})), 0o600))
+
+	_, err := client.DownloadToPath("file_1", path)
+	require.NoError(err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(err)
+	assert.Equal(t, downloadContent, string(data))
+	assert.NoFileExists(t, path+partialFileSuffix)
+	assert.NoFileExists(t, path+partialMetaSuffix)
+}
+
+func TestClient_DownloadToPath_OtherPartialFile(t *testing.T) {
+	server, _ := newDownloadServer(t, false)
+	client := newDownloadClient(server.URL, nil)
+
+	// the partial data is resumed only if it belongs to the same file
+	stale := strings.Repeat("x", 20)
+	tests := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{"same_file", partialMeta(&File{ID: "file_1", Size: 36}), stale + downloadContent[20:]},
+		{"other_file", partialMeta(&File{ID: "file_0", Size: 36}), downloadContent},
+		{"other_size", partialMeta(&File{ID: "file_1", Size: 50}), downloadContent},
+		{"no_meta", "", downloadContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.txt")
+			require.NoError(t, os.WriteFile(path+partialFileSuffix, []byte(stale), 0o600))
+			if tt.meta != "" {
+				require.NoError(t, os.WriteFile(path+partialMetaSuffix, []byte(tt.meta), 0o600))
+			}
+
+			_, err := client.DownloadToPath("file_1", path)
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
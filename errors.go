@@ -0,0 +1,131 @@
+package botgolang
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTransport is returned when the request did not reach the API or the response could not be read.
+	// The underlying network error is wrapped as well, so errors.Is(err, context.Canceled) still works.
+	ErrTransport = errors.New("cannot make request to bot api")
+
+	// ErrInvalidToken is returned when the API rejects the bot token
+	ErrInvalidToken = errors.New("invalid bot token")
+
+	// ErrNotChatMember is returned when the bot is not a member of the requested chat
+	ErrNotChatMember = errors.New("bot is not a member of the chat")
+
+	// ErrDialogNotStarted is returned when the user has never started a dialog with the bot
+	ErrDialogNotStarted = errors.New("user has not started a dialog with the bot")
+
+	// ErrPermissionDenied is returned when the bot has no rights to perform the operation
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrNotFound is returned when the requested chat, message or file does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is returned when the API throttles the bot
+	ErrRateLimited = errors.New("too many requests")
+
+	// ErrServer is returned when the API responds with 5xx status
+	ErrServer = errors.New("bot api server error")
+
+	// ErrFileTooLarge is returned when the downloaded file exceeds the allowed size
+	ErrFileTooLarge = errors.New("file is too large")
+
+	// ErrFileSizeMismatch is returned when the size of the downloaded file differs from File.Size
+	ErrFileSizeMismatch = errors.New("file size mismatch")
+
+	// ErrUnknownAPIError is returned for API errors that do not match any other sentinel
+	ErrUnknownAPIError = errors.New("unknown api error")
+)
+
+// apiErrorDescriptions maps fragments of Response.Description to sentinel errors.
+// The API does not return machine-readable codes, so the order matters: more specific fragments go first.
+var apiErrorDescriptions = []struct {
+	fragment string
+	err      error
+}{
+	{"not a member", ErrNotChatMember},
+	{"not in chat", ErrNotChatMember},
+	{"dialog", ErrDialogNotStarted},
+	{"write first", ErrDialogNotStarted},
+	{"permission", ErrPermissionDenied},
+	{"not allowed", ErrPermissionDenied},
+	{"rate limit", ErrRateLimited},
+	{"too many", ErrRateLimited},
+	{"not found", ErrNotFound},
+	{"token", ErrInvalidToken},
+}
+
+// APIError represents an error returned by the bot API.
+// Use errors.As to get it from any error returned by the library
+// and errors.Is to compare it with ErrInvalidToken, ErrNotChatMember, etc.
+type APIError struct {
+	// HTTP status code of the response
+	StatusCode int
+
+	// Description from the API response or HTTP status text if the body is not a valid response
+	Description string
+
+	// API method path, e.g. /messages/sendText
+	Path string
+
+	// Request id sent along with the request, if any
+	RequestID string
+
+	// Delay requested by the API in Retry-After header, if any
+	RetryAfter time.Duration
+
+	kind error
+}
+
+func newAPIError(statusCode int, description, path, requestID string) *APIError {
+	if description == "" {
+		description = http.StatusText(statusCode)
+	}
+
+	return &APIError{
+		StatusCode:  statusCode,
+		Description: description,
+		Path:        path,
+		RequestID:   requestID,
+		kind:        classifyAPIError(statusCode, description),
+	}
+}
+
+func (e *APIError) Error() string {
+	return "error status from API: " + e.Description
+}
+
+// Unwrap returns the sentinel error matching this API error
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+func classifyAPIError(statusCode int, description string) error {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return ErrInvalidToken
+	case statusCode == http.StatusForbidden:
+		return ErrPermissionDenied
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+
+	description = strings.ToLower(description)
+	for _, known := range apiErrorDescriptions {
+		if strings.Contains(description, known.fragment) {
+			return known.err
+		}
+	}
+
+	return ErrUnknownAPIError
+}
@@ -0,0 +1,208 @@
+package botgolang
+
+// TypedEvent is the event decoded by its type, see Event.Typed:
+//
+//	switch e := event.Typed().(type) {
+//	case *botgolang.NewMessageEvent:
+//		e.Message.Reply("hi")
+//	case *botgolang.CallbackQueryEvent:
+//		e.Response().Send()
+//	}
+type TypedEvent interface {
+	// EventType returns the type of the event
+	EventType() EventType
+}
+
+// NewMessageEvent is the newMessage event
+type NewMessageEvent struct {
+	// Id of the event
+	EventID int
+
+	// The new message with its chat
+	Message *Message
+
+	// Author of the message
+	From Contact
+
+	// Parts of the message
+	Parts []Part
+}
+
+// EditedMessageEvent is the editedMessage event
+type EditedMessageEvent struct {
+	// Id of the event
+	EventID int
+
+	// The message with the new text
+	Message *Message
+
+	// Author of the message
+	From Contact
+
+	// Timestamp of the edit
+	EditedTimestamp int
+}
+
+// DeletedMessageEvent is the deletedMessage event
+type DeletedMessageEvent struct {
+	// Id of the event
+	EventID int
+
+	// Id of the deleted message
+	MsgID string
+
+	// Chat of the deleted message
+	Chat Chat
+
+	// Timestamp of the event
+	Timestamp int
+}
+
+// PinnedMessageEvent is the pinnedMessage event
+type PinnedMessageEvent struct {
+	// Id of the event
+	EventID int
+
+	// The pinned message with its chat
+	Message *Message
+
+	// Author of the message
+	From Contact
+}
+
+// UnpinnedMessageEvent is the unpinnedMessage event
+type UnpinnedMessageEvent struct {
+	// Id of the event
+	EventID int
+
+	// Id of the unpinned message
+	MsgID string
+
+	// Chat of the unpinned message
+	Chat Chat
+
+	// Timestamp of the event
+	Timestamp int
+}
+
+// CallbackQueryEvent is the callbackQuery event sent when the user presses the callback button
+type CallbackQueryEvent struct {
+	client *Client
+
+	// Id of the event
+	EventID int
+
+	// Id of the query to answer
+	QueryID string
+
+	// Callback data of the pressed button
+	CallbackData string
+
+	// The user who pressed the button
+	From Contact
+
+	// The message with the button
+	Message *Message
+}
+
+// ChatMembersJoinedEvent is the newChatMembers event
+type ChatMembersJoinedEvent struct {
+	// Id of the event
+	EventID int
+
+	// The chat the members joined
+	Chat Chat
+
+	// The joined members
+	NewMembers []Contact
+
+	// The user who added the members
+	AddedBy Contact
+}
+
+// ChatMembersLeftEvent is the leftChatMembers event
+type ChatMembersLeftEvent struct {
+	// Id of the event
+	EventID int
+
+	// The chat the members left
+	Chat Chat
+
+	// The members who left
+	LeftMembers []Contact
+
+	// The user who removed the members
+	RemovedBy Contact
+}
+
+// UnknownEvent is the event of the type unknown to the library and not registered
+type UnknownEvent struct {
+	Event Event
+
+	// Error of the registered decoder, nil if there is no decoder
+	Err error
+}
+
+func (e *NewMessageEvent) EventType() EventType        { return NEW_MESSAGE }
+func (e *EditedMessageEvent) EventType() EventType     { return EDITED_MESSAGE }
+func (e *DeletedMessageEvent) EventType() EventType    { return DELETED_MESSAGE }
+func (e *PinnedMessageEvent) EventType() EventType     { return PINNED_MESSAGE }
+func (e *UnpinnedMessageEvent) EventType() EventType   { return UNPINNED_MESSAGE }
+func (e *CallbackQueryEvent) EventType() EventType     { return CALLBACK_QUERY }
+func (e *ChatMembersJoinedEvent) EventType() EventType { return NEW_CHAT_MEMBERS }
+func (e *ChatMembersLeftEvent) EventType() EventType   { return LEFT_CHAT_MEMBERS }
+func (e *UnknownEvent) EventType() EventType           { return e.Event.Type }
+
+// Response returns the answer to the query, set its text or url and send it
+func (e *CallbackQueryEvent) Response() *ButtonResponse {
+	return &ButtonResponse{
+		client:       e.client,
+		QueryID:      e.QueryID,
+		CallbackData: e.CallbackData,
+	}
+}
+
+// Typed returns the event decoded by its type, only the fields presented in events of the type are set.
+// Events of types registered with RegisterEventType are decoded by their decoders,
+// events of unknown types are returned as *UnknownEvent.
+func (e *Event) Typed() TypedEvent {
+	if decoder := eventDecoder(e.Type); decoder != nil {
+		typed, err := decoder(e)
+		if err != nil {
+			return &UnknownEvent{Event: *e, Err: err}
+		}
+		return typed
+	}
+
+	p := &e.Payload
+	chat := p.Chat
+	chat.client = p.client
+
+	switch e.Type {
+	case NEW_MESSAGE:
+		return &NewMessageEvent{EventID: e.EventID, Message: p.Message(), From: p.From, Parts: p.Parts}
+	case EDITED_MESSAGE:
+		return &EditedMessageEvent{EventID: e.EventID, Message: p.Message(), From: p.From, EditedTimestamp: p.EditedTimestamp}
+	case DELETED_MESSAGE:
+		return &DeletedMessageEvent{EventID: e.EventID, MsgID: p.MsgID, Chat: chat, Timestamp: p.Timestamp}
+	case PINNED_MESSAGE:
+		return &PinnedMessageEvent{EventID: e.EventID, Message: p.Message(), From: p.From}
+	case UNPINNED_MESSAGE:
+		return &UnpinnedMessageEvent{EventID: e.EventID, MsgID: p.MsgID, Chat: chat, Timestamp: p.Timestamp}
+	case CALLBACK_QUERY:
+		return &CallbackQueryEvent{
+			client:       p.client,
+			EventID:      e.EventID,
+			QueryID:      p.QueryID,
+			CallbackData: p.CallbackData,
+			From:         p.From,
+			Message:      p.CallbackMessage(),
+		}
+	case NEW_CHAT_MEMBERS:
+		return &ChatMembersJoinedEvent{EventID: e.EventID, Chat: chat, NewMembers: p.NewMembers, AddedBy: p.AddedBy}
+	case LEFT_CHAT_MEMBERS:
+		return &ChatMembersLeftEvent{EventID: e.EventID, Chat: chat, LeftMembers: p.LeftMembers, RemovedBy: p.RemovedBy}
+	}
+
+	return &UnknownEvent{Event: *e}
+}
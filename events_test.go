@@ -0,0 +1,90 @@
+package botgolang
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockEvents(t *testing.T) []*Event {
+	w := httptest.NewRecorder()
+	(&MockHandler{}).GetEvents(w)
+
+	response := eventsResponse{}
+	require.NoError(t, response.UnmarshalJSON(w.Body.Bytes()))
+	require.Len(t, response.Events, 8)
+	return response.Events
+}
+
+func TestEvent_Typed(t *testing.T) {
+	assert := assert.New(t)
+
+	events := mockEvents(t)
+
+	newMessage, ok := events[0].Typed().(*NewMessageEvent)
+	if assert.True(ok) {
+		assert.Equal(1, newMessage.EventID)
+		assert.Equal("Hello!", newMessage.Message.Text)
+		assert.Equal("681869378@chat.agent", newMessage.Message.Chat.ID)
+		assert.Equal("1234567890", newMessage.From.ID)
+		assert.Len(newMessage.Parts, 6)
+	}
+
+	edited, ok := events[1].Typed().(*EditedMessageEvent)
+	if assert.True(ok) {
+		assert.Equal(1546290099, edited.EditedTimestamp)
+		assert.Equal("57883346846815030", edited.Message.ID)
+	}
+
+	deleted, ok := events[2].Typed().(*DeletedMessageEvent)
+	if assert.True(ok) {
+		assert.Equal("57883346846815030", deleted.MsgID)
+		assert.Equal("681869378@chat.agent", deleted.Chat.ID)
+	}
+
+	pinned, ok := events[3].Typed().(*PinnedMessageEvent)
+	if assert.True(ok) {
+		assert.Equal("Some important information!", pinned.Message.Text)
+		assert.Equal("9876543210", pinned.From.ID)
+	}
+
+	unpinned, ok := events[4].Typed().(*UnpinnedMessageEvent)
+	if assert.True(ok) {
+		assert.Equal("6720509406122810000", unpinned.MsgID)
+	}
+
+	joined, ok := events[5].Typed().(*ChatMembersJoinedEvent)
+	if assert.True(ok) {
+		assert.Equal(Group, joined.Chat.Type)
+		assert.Equal([]Contact{{User: User{ID: "1234567890"}, FirstName: "Name", LastName: "SurName"}}, joined.NewMembers)
+		assert.Equal("9876543210", joined.AddedBy.ID)
+	}
+
+	left, ok := events[6].Typed().(*ChatMembersLeftEvent)
+	if assert.True(ok) {
+		assert.Len(left.LeftMembers, 1)
+		assert.Equal("9876543210", left.RemovedBy.ID)
+	}
+
+	callback, ok := events[7].Typed().(*CallbackQueryEvent)
+	if assert.True(ok) {
+		assert.Equal("SVR:123456", callback.QueryID)
+		assert.Equal("echo", callback.CallbackData)
+		assert.Equal("1234567890", callback.From.ID)
+		assert.Equal("6720509406122810000", callback.Message.ID)
+		assert.Equal("SVR:123456", callback.Response().QueryID)
+	}
+
+	for _, event := range events {
+		assert.Equal(event.Type, event.Typed().EventType())
+	}
+
+	unknown := &Event{EventID: 9, Type: "changedChatInfo"}
+	typed, ok := unknown.Typed().(*UnknownEvent)
+	if assert.True(ok) {
+		assert.Equal(9, typed.Event.EventID)
+		assert.Equal(EventType("changedChatInfo"), typed.EventType())
+	}
+}
@@ -18,7 +18,11 @@ func main() {
 		log.Fatalf("cannot connect to bot: %s", err)
 	}
 
-	log.Println(bot.Info)
+	info, err := bot.Self(context.Background())
+	if err != nil {
+		log.Fatalf("cannot get info about bot: %s", err)
+	}
+	log.Println(info)
 
 	message := bot.NewTextMessage("d.dorofeev@corp.mail.ru", "Hi")
 	if err = message.Send(); err != nil {
@@ -0,0 +1,188 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const eventRateWindow = time.Minute
+
+var (
+	// ErrUpdaterRunning is returned by Updater.Start if the updater is already started
+	ErrUpdaterRunning = errors.New("updater is already running")
+)
+
+// UpdaterStatus is the snapshot of the updater state
+type UpdaterStatus struct {
+	// Running is true while the updater polls events
+	Running bool
+
+	// Paused is true between Pause and Resume
+	Paused bool
+
+	// Id of the last received event
+	LastEventID int
+
+	// Time of the last successful poll
+	LastPollTime time.Time
+
+	// Time of the last delivered event
+	LastEventTime time.Time
+
+	// The last poll error and its time, they are kept after the updater recovers
+	LastError     error
+	LastErrorTime time.Time
+
+	// Number of failed polls in a row, 0 if the last poll succeeded
+	Failures int
+
+	// Average number of events delivered per second during the last minute
+	EventsPerSecond float64
+}
+
+// Start starts polling events in the background and returns the channel they are delivered to.
+// The channel is closed after Stop or when ctx is done.
+func (u *Updater) Start(ctx context.Context) (<-chan Event, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.cancel != nil {
+		return nil, ErrUpdaterRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	u.cancel, u.stop, u.done = cancel, stop, done
+
+	ch := make(chan Event, u.bufferSize)
+	go func() {
+		u.run(ctx, ch, stop)
+		cancel()
+
+		u.mu.Lock()
+		if u.done == done {
+			u.cancel, u.stop, u.done = nil, nil, nil
+		}
+		u.mu.Unlock()
+
+		close(done)
+	}()
+
+	return ch, nil
+}
+
+// Stop stops the updater started by Start. It lets the in-flight poll finish and deliver
+// its events, and waits until the channel is closed. If ctx is done earlier,
+// the poll is canceled and ctx error is returned. Stop of a stopped updater does nothing.
+func (u *Updater) Stop(ctx context.Context) error {
+	u.mu.Lock()
+	cancel, stop, done := u.cancel, u.stop, u.done
+	if cancel == nil {
+		u.mu.Unlock()
+		return nil
+	}
+	select {
+	case <-stop:
+	default:
+		close(stop)
+	}
+	u.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Pause suspends polling after the in-flight poll, e.g. during maintenance.
+// Events which are already received are still delivered.
+func (u *Updater) Pause() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.resumed == nil {
+		u.resumed = make(chan struct{})
+	}
+}
+
+// Resume continues polling suspended by Pause
+func (u *Updater) Resume() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.resumed != nil {
+		close(u.resumed)
+		u.resumed = nil
+	}
+}
+
+// Status returns the snapshot of the updater state, it is safe to call concurrently
+func (u *Updater) Status() UpdaterStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return UpdaterStatus{
+		Running:         u.runs > 0,
+		Paused:          u.resumed != nil,
+		LastEventID:     u.lastEventID,
+		LastPollTime:    u.lastPollTime,
+		LastEventTime:   u.lastEventTime,
+		LastError:       u.lastError,
+		LastErrorTime:   u.lastErrorTime,
+		Failures:        u.failures,
+		EventsPerSecond: u.eventRate.rate(time.Now()),
+	}
+}
+
+// waitResumed blocks while the updater is paused, it returns false if the updater must stop
+func (u *Updater) waitResumed(ctx context.Context, stop <-chan struct{}) bool {
+	for {
+		u.mu.Lock()
+		resumed := u.resumed
+		u.mu.Unlock()
+
+		if resumed == nil {
+			return true
+		}
+
+		select {
+		case <-resumed:
+		case <-stop:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// rateCounter counts events in one-second buckets over the sliding window
+type rateCounter struct {
+	buckets [int(eventRateWindow / time.Second)]uint64
+	seconds [int(eventRateWindow / time.Second)]int64
+}
+
+func (c *rateCounter) add(now time.Time, n int) {
+	second := now.Unix()
+	i := int(second % int64(len(c.buckets)))
+	if c.seconds[i] != second {
+		c.seconds[i], c.buckets[i] = second, 0
+	}
+	c.buckets[i] += uint64(n)
+}
+
+func (c *rateCounter) rate(now time.Time) float64 {
+	second := now.Unix()
+	var total uint64
+	for i := range c.buckets {
+		if second-c.seconds[i] < int64(len(c.buckets)) {
+			total += c.buckets[i]
+		}
+	}
+	return float64(total) / eventRateWindow.Seconds()
+}
@@ -0,0 +1,167 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleTestUpdater(handler http.Handler) (*Updater, func()) {
+	testServer := httptest.NewServer(handler)
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  &http.Client{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	return newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{})), testServer.Close
+}
+
+func TestUpdater_StartStop(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var polls int32
+	release := make(chan struct{})
+	updater, closeServer := newLifecycleTestUpdater(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) > 1 {
+			<-r.Context().Done()
+			return
+		}
+		<-release
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer closeServer()
+
+	ch, err := updater.Start(context.Background())
+	require.NoError(err)
+	_, err = updater.Start(context.Background())
+	assert.ErrorIs(err, ErrUpdaterRunning)
+
+	assert.Eventually(func() bool { return atomic.LoadInt32(&polls) == 1 }, time.Second, time.Millisecond)
+	assert.True(updater.Status().Running)
+
+	stopped := make(chan error)
+	go func() { stopped <- updater.Stop(context.Background()) }()
+
+	select {
+	case <-stopped:
+		t.Fatal("stop has not waited for the in-flight poll")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	var events int
+	for range ch {
+		events++
+	}
+	assert.Equal(8, events)
+	assert.NoError(<-stopped)
+	assert.EqualValues(1, atomic.LoadInt32(&polls))
+
+	status := updater.Status()
+	assert.False(status.Running)
+	assert.Equal(8, status.LastEventID)
+	assert.False(status.LastEventTime.IsZero())
+	assert.Greater(status.EventsPerSecond, 0.0)
+
+	_, err = updater.Start(context.Background())
+	assert.NoError(err)
+	assert.NoError(updater.Stop(context.Background()))
+}
+
+func TestUpdater_StopDrain(t *testing.T) {
+	var polls int32
+	updater, closeServer := newLifecycleTestUpdater(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) > 1 {
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"ok":true,"events":[]}`))
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer closeServer()
+	updater.bufferSize = 8
+
+	ch, err := updater.Start(context.Background())
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(ch) == 8 }, time.Second, time.Millisecond)
+	require.NoError(t, updater.Stop(context.Background()))
+
+	// the buffered events are processed after the updater has stopped
+	var events int
+	for event := range ch {
+		events++
+		assert.NoError(t, event.Context().Err())
+	}
+	assert.Equal(t, 8, events)
+}
+
+func TestUpdater_StopTimeout(t *testing.T) {
+	polling := make(chan struct{}, 1)
+	updater, closeServer := newLifecycleTestUpdater(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polling <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer closeServer()
+
+	ch, err := updater.Start(context.Background())
+	require.NoError(t, err)
+	<-polling
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, updater.Stop(ctx), context.DeadlineExceeded)
+
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.NoError(t, updater.Stop(context.Background()))
+}
+
+func TestUpdater_PauseResume(t *testing.T) {
+	assert := assert.New(t)
+
+	var polls int32
+	updater, closeServer := newLifecycleTestUpdater(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer closeServer()
+	updater.poll = PollPolicy{Backoff: &ExponentialBackoff{InitialInterval: time.Millisecond}}.withDefaults()
+
+	updater.Pause()
+	assert.True(updater.Status().Paused)
+
+	_, err := updater.Start(context.Background())
+	require.NoError(t, err)
+	defer func() { _ = updater.Stop(context.Background()) }()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.EqualValues(0, atomic.LoadInt32(&polls))
+
+	updater.Resume()
+	assert.Eventually(func() bool { return updater.Status().Failures > 1 }, time.Second, time.Millisecond)
+
+	status := updater.Status()
+	assert.False(status.Paused)
+	assert.ErrorIs(status.LastError, ErrServer)
+	assert.False(status.LastErrorTime.IsZero())
+}
+
+func TestRateCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	counter := rateCounter{}
+	counter.add(now, 30)
+	counter.add(now.Add(time.Second), 30)
+
+	assert.Equal(t, 1.0, counter.rate(now.Add(time.Second)))
+	assert.Equal(t, 0.5, counter.rate(now.Add(time.Minute)))
+	assert.Equal(t, 0.0, counter.rate(now.Add(2*time.Minute)))
+}
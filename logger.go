@@ -0,0 +1,98 @@
+package botgolang
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogFields are structured fields attached to a log message
+type LogFields map[string]interface{}
+
+// Logger is the interface the library writes its logs to.
+// Use NewLogrusLogger or NewSlogLogger adapters or implement it for your logging library.
+type Logger interface {
+	Debug(msg string, fields LogFields)
+	Info(msg string, fields LogFields)
+	Warn(msg string, fields LogFields)
+	Error(msg string, fields LogFields)
+
+	// DebugEnabled reports whether debug messages are written.
+	// It is used to skip building expensive debug fields.
+	DebugEnabled() bool
+}
+
+type logrusLogger struct {
+	logger *logrus.Logger
+}
+
+// NewLogrusLogger returns Logger writing to logrus
+func NewLogrusLogger(logger *logrus.Logger) Logger {
+	return &logrusLogger{logger: logger}
+}
+
+func (l *logrusLogger) Debug(msg string, fields LogFields) {
+	l.logger.WithFields(logrus.Fields(fields)).Debug(msg)
+}
+
+func (l *logrusLogger) Info(msg string, fields LogFields) {
+	l.logger.WithFields(logrus.Fields(fields)).Info(msg)
+}
+
+func (l *logrusLogger) Warn(msg string, fields LogFields) {
+	l.logger.WithFields(logrus.Fields(fields)).Warn(msg)
+}
+
+func (l *logrusLogger) Error(msg string, fields LogFields) {
+	l.logger.WithFields(logrus.Fields(fields)).Error(msg)
+}
+
+func (l *logrusLogger) DebugEnabled() bool {
+	return l.logger.IsLevelEnabled(logrus.DebugLevel)
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns Logger writing to log/slog
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) log(level slog.Level, msg string, fields LogFields) {
+	if !l.logger.Enabled(context.Background(), level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		attrs = append(attrs, slog.Any(key, value))
+	}
+
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (l *slogLogger) Debug(msg string, fields LogFields) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l *slogLogger) Info(msg string, fields LogFields) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l *slogLogger) Warn(msg string, fields LogFields) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l *slogLogger) Error(msg string, fields LogFields) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+func (l *slogLogger) DebugEnabled() bool {
+	return l.logger.Enabled(context.Background(), slog.LevelDebug)
+}
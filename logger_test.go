@@ -0,0 +1,54 @@
+package botgolang
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	assert.False(t, logger.DebugEnabled())
+
+	logger.Debug("hidden", nil)
+	logger.Error("request error", LogFields{"err": errors.New("boom"), "endpoint": "/self/get"})
+
+	record := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "request error", record["msg"])
+	assert.Equal(t, "boom", record["err"])
+	assert.Equal(t, "/self/get", record["endpoint"])
+}
+
+func TestClient_Do_StructuredLogs(t *testing.T) {
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	output := &bytes.Buffer{}
+	client := newClient(http.DefaultClient, testServer.URL, "test_token",
+		NewSlogLogger(slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	_, err := client.GetChatInfo("id_1234")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+
+	record := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "got response from API", record["msg"])
+	assert.Equal(t, "/chats/getInfo", record["endpoint"])
+	assert.Equal(t, "id_1234", record["chatId"])
+	assert.Contains(t, record, "latency")
+}
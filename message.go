@@ -1,7 +1,9 @@
 package botgolang
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -29,6 +31,17 @@ type Message struct {
 	// File contains file attachment of the message
 	File *os.File `json:"-"`
 
+	// Reader contains file attachment of the message which is not stored on the disk.
+	// It is streamed to the API, use it with FileName.
+	Reader io.Reader `json:"-"`
+
+	// Name of the file attached with Reader
+	FileName string `json:"-"`
+
+	// Size of the data in Reader in bytes, if known.
+	// It is detected automatically for *bytes.Reader, *strings.Reader, *bytes.Buffer and regular files.
+	FileSize int64 `json:"-"`
+
 	// Id of file to send
 	FileID string `json:"fileId"`
 
@@ -73,6 +86,14 @@ func (m *Message) AttachNewFile(file *os.File) {
 	m.ContentType = OtherFile
 }
 
+// AttachNewReader attaches data from the reader as a new file with the given name.
+// The data is streamed to the API without buffering the whole file in memory.
+func (m *Message) AttachNewReader(name string, reader io.Reader) {
+	m.Reader = reader
+	m.FileName = name
+	m.ContentType = OtherFile
+}
+
 func (m *Message) AttachExistingFile(fileID string) {
 	m.FileID = fileID
 	m.ContentType = OtherFile
@@ -83,11 +104,32 @@ func (m *Message) AttachNewVoice(file *os.File) {
 	m.ContentType = Voice
 }
 
+// AttachNewVoiceReader attaches data from the reader as a new voice message with the given name.
+// Use .aac, .ogg or .m4a extension in the name.
+func (m *Message) AttachNewVoiceReader(name string, reader io.Reader) {
+	m.Reader = reader
+	m.FileName = name
+	m.ContentType = Voice
+}
+
 func (m *Message) AttachExistingVoice(fileID string) {
 	m.FileID = fileID
 	m.ContentType = Voice
 }
 
+func (m *Message) hasNewFile() bool {
+	return m.File != nil || m.Reader != nil
+}
+
+// fileUpload returns the new file attached to the message, Reader takes precedence over File
+func (m *Message) fileUpload() *fileUpload {
+	if m.Reader != nil {
+		return newFileUpload(m.FileName, m.Reader, m.FileSize)
+	}
+
+	return osFileUpload(m.File)
+}
+
 type ParentMessage struct {
 	ChatID string `json:"chatId"`
 	MsgID  int64  `json:"messageId"`
@@ -117,6 +159,11 @@ func (m *Message) AttachInlineKeyboard(keyboard Keyboard) {
 // Send method sends your message.
 // Make sure you have Text or FileID in your message.
 func (m *Message) Send() error {
+	return m.SendWithContext(context.Background())
+}
+
+// SendWithContext is the same as Send, but the request is bound to ctx
+func (m *Message) SendWithContext(ctx context.Context) error {
 	if m.client == nil {
 		return fmt.Errorf("client is not inited, create message with constructor NewMessage, NewTextMessage, etc")
 	}
@@ -128,43 +175,43 @@ func (m *Message) Send() error {
 	switch m.ContentType {
 	case Voice:
 		if m.FileID != "" {
-			return m.client.SendVoiceMessage(m)
+			return m.client.SendVoiceMessageWithContext(ctx, m)
 		}
 
-		if m.File != nil {
-			return m.client.UploadVoice(m)
+		if m.hasNewFile() {
+			return m.client.UploadVoiceWithContext(ctx, m)
 		}
 	case OtherFile:
 		if m.FileID != "" {
-			return m.client.SendFileMessage(m)
+			return m.client.SendFileMessageWithContext(ctx, m)
 		}
 
-		if m.File != nil {
-			return m.client.UploadFile(m)
+		if m.hasNewFile() {
+			return m.client.UploadFileWithContext(ctx, m)
 		}
 	case Text:
-		return m.client.SendTextMessage(m)
+		return m.client.SendTextMessageWithContext(ctx, m)
 	case Deeplink:
-		return m.client.SendTextWithDeeplinkMessage(m)
+		return m.client.SendTextWithDeeplinkMessageWithContext(ctx, m)
 	case Unknown:
 		// need to autodetect
 		if m.FileID != "" {
 			// voice message's fileID always starts with 'I'
 			if m.FileID[0] == voiceMessageLeadingRune {
-				return m.client.SendVoiceMessage(m)
+				return m.client.SendVoiceMessageWithContext(ctx, m)
 			}
-			return m.client.SendFileMessage(m)
+			return m.client.SendFileMessageWithContext(ctx, m)
 		}
 
-		if m.File != nil {
-			if voiceMessageSupportedExtensions[filepath.Ext(m.File.Name())] {
-				return m.client.UploadVoice(m)
+		if m.hasNewFile() {
+			if voiceMessageSupportedExtensions[filepath.Ext(m.fileUpload().name)] {
+				return m.client.UploadVoiceWithContext(ctx, m)
 			}
-			return m.client.UploadFile(m)
+			return m.client.UploadFileWithContext(ctx, m)
 		}
 
 		if m.Text != "" {
-			return m.client.SendTextMessage(m)
+			return m.client.SendTextMessageWithContext(ctx, m)
 		}
 	}
 
@@ -174,25 +221,40 @@ func (m *Message) Send() error {
 // Edit method edits your message.
 // Make sure you have ID in your message.
 func (m *Message) Edit() error {
+	return m.EditWithContext(context.Background())
+}
+
+// EditWithContext is the same as Edit, but the request is bound to ctx
+func (m *Message) EditWithContext(ctx context.Context) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot edit message without id")
 	}
-	return m.client.EditMessage(m)
+	return m.client.EditMessageWithContext(ctx, m)
 }
 
 // Delete method deletes your message.
 // Make sure you have ID in your message.
 func (m *Message) Delete() error {
+	return m.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext is the same as Delete, but the request is bound to ctx
+func (m *Message) DeleteWithContext(ctx context.Context) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot delete message without id")
 	}
 
-	return m.client.DeleteMessage(m)
+	return m.client.DeleteMessageWithContext(ctx, m)
 }
 
 // Reply method replies to the message.
 // Make sure you have ID in the message.
 func (m *Message) Reply(text string) error {
+	return m.ReplyWithContext(context.Background(), text)
+}
+
+// ReplyWithContext is the same as Reply, but the request is bound to ctx
+func (m *Message) ReplyWithContext(ctx context.Context, text string) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot reply to message without id")
 	}
@@ -200,12 +262,17 @@ func (m *Message) Reply(text string) error {
 	m.ReplyMsgID = m.ID
 	m.Text = text
 
-	return m.client.SendTextMessage(m)
+	return m.client.SendTextMessageWithContext(ctx, m)
 }
 
 // Forward method forwards your message to chat.
 // Make sure you have ID in your message.
 func (m *Message) Forward(chatID string) error {
+	return m.ForwardWithContext(context.Background(), chatID)
+}
+
+// ForwardWithContext is the same as Forward, but the request is bound to ctx
+func (m *Message) ForwardWithContext(ctx context.Context, chatID string) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot forward message without id")
 	}
@@ -214,25 +281,35 @@ func (m *Message) Forward(chatID string) error {
 	m.ForwardMsgID = m.ID
 	m.Chat.ID = chatID
 
-	return m.client.SendTextMessage(m)
+	return m.client.SendTextMessageWithContext(ctx, m)
 }
 
 // Pin message in chat
 // Make sure you are admin in this chat
 func (m *Message) Pin() error {
+	return m.PinWithContext(context.Background())
+}
+
+// PinWithContext is the same as Pin, but the request is bound to ctx
+func (m *Message) PinWithContext(ctx context.Context) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot pin message without id")
 	}
 
-	return m.client.PinMessage(m)
+	return m.client.PinMessageWithContext(ctx, m)
 }
 
 // Unpin message in chat
 // Make sure you are admin in this chat
 func (m *Message) Unpin() error {
+	return m.UnpinWithContext(context.Background())
+}
+
+// UnpinWithContext is the same as Unpin, but the request is bound to ctx
+func (m *Message) UnpinWithContext(ctx context.Context) error {
 	if m.ID == "" {
 		return fmt.Errorf("cannot unpin message without id")
 	}
 
-	return m.client.UnpinMessage(m)
+	return m.client.UnpinMessageWithContext(ctx, m)
 }
@@ -0,0 +1,321 @@
+package botgolang
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	metricsNamespace = "botgolang"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	resultOK             = "ok"
+	resultAPIError       = "api_error"
+	resultTransportError = "transport_error"
+
+	// unknown types are sent by the server, the rest of them is counted as unknownTypeOther
+	maxUnknownTypeLabels = 50
+	unknownTypeOther     = "other"
+)
+
+// DefaultMetricsBuckets are upper bounds of request duration histograms in seconds.
+// They cover long polling of /events/get which lasts up to the poll time.
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Metrics collects statistics of API calls and the update loop and exposes them
+// in the Prometheus text exposition format. It implements http.Handler:
+//
+//	metrics := botgolang.NewMetrics()
+//	bot, err := botgolang.NewBot(BOT_TOKEN, botgolang.BotMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// Use a separate Metrics for every bot, the updater gauges are not labeled with the bot.
+type Metrics struct {
+	mu sync.Mutex
+
+	buckets   []float64
+	requests  map[requestKey]uint64
+	durations map[string]*histogram
+	events    map[EventType]uint64
+	unknown   map[unknownKey]uint64
+
+	lastPoll    time.Time
+	lastEventID int
+
+	dispatcher *Dispatcher
+	handling   *histogram
+
+	now func() time.Time
+}
+
+type requestKey struct {
+	path   string
+	result string
+}
+
+type unknownKey struct {
+	kind     string
+	typeName string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics returns Metrics with DefaultMetricsBuckets.
+// Pass custom bucket upper bounds in seconds to override them.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		buckets:   sorted,
+		requests:  map[requestKey]uint64{},
+		durations: map[string]*histogram{},
+		events:    map[EventType]uint64{},
+		unknown:   map[unknownKey]uint64{},
+		now:       time.Now,
+	}
+}
+
+// Middleware returns the middleware counting calls and measuring their duration.
+// BotMetrics adds it automatically.
+func (m *Metrics) Middleware() Middleware {
+	return func(next CallHandler) CallHandler {
+		return func(ctx context.Context, call *Call) (*CallResult, error) {
+			start := m.now()
+			result, err := next(ctx, call)
+			m.observeRequest(call.Path, callResult(err), m.now().Sub(start))
+			return result, err
+		}
+	}
+}
+
+func callResult(err error) string {
+	apiErr := &APIError{}
+	switch {
+	case err == nil:
+		return resultOK
+	case errors.As(err, &apiErr):
+		return resultAPIError
+	}
+	return resultTransportError
+}
+
+func (m *Metrics) observeRequest(path, result string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{path: path, result: result}]++
+
+	h, ok := m.durations[path]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[path] = h
+	}
+
+	h.observe(m.buckets, duration)
+}
+
+func (h *histogram) observe(buckets []float64, duration time.Duration) {
+	seconds := duration.Seconds()
+	for i, bound := range buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// setDispatcher exports the statistics of the dispatcher
+func (m *Metrics) setDispatcher(d *Dispatcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dispatcher = d
+	if m.handling == nil {
+		m.handling = &histogram{counts: make([]uint64, len(m.buckets))}
+	}
+}
+
+// observeHandling records the duration of the event handling by the dispatcher
+func (m *Metrics) observeHandling(duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handling.observe(m.buckets, duration)
+}
+
+// observePoll records the successful poll of events
+func (m *Metrics) observePoll(events []*Event, lastEventID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastPoll = m.now()
+	m.lastEventID = lastEventID
+	for _, event := range events {
+		m.events[event.Type]++
+	}
+}
+
+// observeUnknownType counts the event or the part of the unknown type.
+// The number of type labels is limited, so a misbehaving server cannot blow up the metrics.
+func (m *Metrics) observeUnknownType(kind, typeName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := unknownKey{kind: kind, typeName: typeName}
+	if _, ok := m.unknown[key]; !ok && len(m.unknown) >= maxUnknownTypeLabels {
+		key.typeName = unknownTypeOther
+	}
+	m.unknown[key]++
+}
+
+// ServeHTTP writes metrics in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+
+	buf := bufio.NewWriter(w)
+	m.write(buf)
+	_ = buf.Flush()
+}
+
+func (m *Metrics) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := metricsNamespace + "_api_requests_total"
+	writeHeader(w, name, "counter", "Number of API calls by path and result.")
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].result < keys[j].result
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{path=%s,result=%s} %d\n", name, labelValue(key.path), labelValue(key.result), m.requests[key])
+	}
+
+	name = metricsNamespace + "_api_request_duration_seconds"
+	writeHeader(w, name, "histogram", "Duration of API calls including retries.")
+	paths := make([]string, 0, len(m.durations))
+	for path := range m.durations {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		h := m.durations[path]
+		label := labelValue(path)
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "%s_bucket{path=%s,le=\"%s\"} %d\n", name, label, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{path=%s,le=\"+Inf\"} %d\n", name, label, h.count)
+		fmt.Fprintf(w, "%s_sum{path=%s} %s\n", name, label, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{path=%s} %d\n", name, label, h.count)
+	}
+
+	name = metricsNamespace + "_events_received_total"
+	writeHeader(w, name, "counter", "Number of events received by type.")
+	types := make([]string, 0, len(m.events))
+	for eventType := range m.events {
+		types = append(types, string(eventType))
+	}
+	sort.Strings(types)
+	for _, eventType := range types {
+		fmt.Fprintf(w, "%s{type=%s} %d\n", name, labelValue(eventType), m.events[EventType(eventType)])
+	}
+
+	name = metricsNamespace + "_unknown_types_total"
+	writeHeader(w, name, "counter", "Number of received events and parts of unknown types, types over the limit are counted as other.")
+	unknown := make([]unknownKey, 0, len(m.unknown))
+	for key := range m.unknown {
+		unknown = append(unknown, key)
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		if unknown[i].kind != unknown[j].kind {
+			return unknown[i].kind < unknown[j].kind
+		}
+		return unknown[i].typeName < unknown[j].typeName
+	})
+	for _, key := range unknown {
+		fmt.Fprintf(w, "%s{kind=%s,type=%s} %d\n", name, labelValue(key.kind), labelValue(key.typeName), m.unknown[key])
+	}
+
+	if !m.lastPoll.IsZero() {
+		name = metricsNamespace + "_updater_seconds_since_last_poll"
+		writeHeader(w, name, "gauge", "Time since the last successful poll of events.")
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(m.now().Sub(m.lastPoll).Seconds()))
+	}
+
+	name = metricsNamespace + "_updater_last_event_id"
+	writeHeader(w, name, "gauge", "Id of the last event received by the updater.")
+	fmt.Fprintf(w, "%s %d\n", name, m.lastEventID)
+
+	if m.dispatcher != nil {
+		m.writeDispatcher(w)
+	}
+}
+
+func (m *Metrics) writeDispatcher(w *bufio.Writer) {
+	stats := m.dispatcher.Stats()
+
+	name := metricsNamespace + "_dispatcher_queued_events"
+	writeHeader(w, name, "gauge", "Number of events waiting in the dispatcher queues.")
+	fmt.Fprintf(w, "%s %d\n", name, stats.Queued)
+
+	name = metricsNamespace + "_dispatcher_max_queue_length"
+	writeHeader(w, name, "gauge", "Length of the longest dispatcher queue.")
+	fmt.Fprintf(w, "%s %d\n", name, stats.MaxQueueLength)
+
+	name = metricsNamespace + "_dispatcher_in_flight_events"
+	writeHeader(w, name, "gauge", "Number of events being handled.")
+	fmt.Fprintf(w, "%s %d\n", name, stats.InFlight)
+
+	name = metricsNamespace + "_dispatcher_blocked_total"
+	writeHeader(w, name, "counter", "Number of times dispatching waited for a full queue.")
+	fmt.Fprintf(w, "%s %d\n", name, stats.Blocked)
+
+	name = metricsNamespace + "_dispatcher_handling_duration_seconds"
+	writeHeader(w, name, "histogram", "Duration of event handling.")
+	for i, bound := range m.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, formatFloat(bound), m.handling.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, m.handling.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(m.handling.sum))
+	fmt.Fprintf(w, "%s_count %d\n", name, m.handling.count)
+}
+
+func writeHeader(w *bufio.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelValue(value string) string {
+	return `"` + labelReplacer.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,96 @@
+package botgolang
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	metrics := NewMetrics(0.1, 1)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics.now = func() time.Time { return now }
+
+	client := &Client{
+		baseURL:     testServer.URL,
+		token:       "test_token",
+		client:      http.DefaultClient,
+		logger:      NewLogrusLogger(&logrus.Logger{}),
+		middlewares: []Middleware{metrics.Middleware()},
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.metrics = metrics
+
+	_, err := client.Do("/messages/sendText", url.Values{}, nil)
+	require.NoError(err)
+	client.token = ""
+	_, err = client.Do("/messages/sendText", url.Values{}, nil)
+	require.ErrorIs(err, ErrInvalidToken)
+	client.token = "test_token"
+
+	client.baseURL = "http://127.0.0.1:0"
+	_, err = client.Do("/messages/sendText", url.Values{}, nil)
+	require.ErrorIs(err, ErrTransport)
+
+	client.baseURL = testServer.URL
+	_, err = updater.GetLastEvents(0)
+	require.NoError(err)
+
+	now = now.Add(1500 * time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(metricsContentType, recorder.Header().Get("Content-Type"))
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(err)
+	text := string(body)
+
+	assert.Contains(text, "# TYPE botgolang_api_requests_total counter\n")
+	assert.Contains(text, `botgolang_api_requests_total{path="/messages/sendText",result="ok"} 1`+"\n")
+	assert.Contains(text, `botgolang_api_requests_total{path="/messages/sendText",result="api_error"} 1`+"\n")
+	assert.Contains(text, `botgolang_api_requests_total{path="/messages/sendText",result="transport_error"} 1`+"\n")
+	assert.Contains(text, `botgolang_api_requests_total{path="/events/get",result="ok"} 1`+"\n")
+
+	assert.Contains(text, "# TYPE botgolang_api_request_duration_seconds histogram\n")
+	assert.Contains(text, `botgolang_api_request_duration_seconds_bucket{path="/messages/sendText",le="0.1"} 3`+"\n")
+	assert.Contains(text, `botgolang_api_request_duration_seconds_bucket{path="/messages/sendText",le="+Inf"} 3`+"\n")
+	assert.Contains(text, `botgolang_api_request_duration_seconds_count{path="/messages/sendText"} 3`+"\n")
+
+	assert.Contains(text, `botgolang_events_received_total{type="newMessage"} 1`+"\n")
+	assert.Contains(text, `botgolang_events_received_total{type="callbackQuery"} 1`+"\n")
+	assert.Contains(text, "botgolang_updater_seconds_since_last_poll 1.5\n")
+	assert.Contains(text, "botgolang_updater_last_event_id 8\n")
+}
+
+func TestLabelValue(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, labelValue("a\\b\"c\nd"))
+}
+
+func TestMetrics_UnknownTypesLimit(t *testing.T) {
+	metrics := NewMetrics()
+	for i := 0; i < maxUnknownTypeLabels+10; i++ {
+		metrics.observeUnknownType(unknownKindPart, fmt.Sprintf("type%d", i))
+	}
+	metrics.observeUnknownType(unknownKindPart, "type0")
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `botgolang_unknown_types_total{kind="part",type="type0"} 2`)
+	assert.Contains(t, w.Body.String(), `botgolang_unknown_types_total{kind="part",type="other"} 10`)
+	assert.NotContains(t, w.Body.String(), `type="type55"`)
+}
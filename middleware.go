@@ -0,0 +1,92 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Call describes a request to the API passed through the middleware chain.
+// Middlewares can change the path, params and headers before passing the call further.
+type Call struct {
+	// API method path, e.g. /messages/sendText
+	Path string
+
+	// Request params without the token
+	Params url.Values
+
+	// Additional HTTP headers of the request
+	Header http.Header
+
+	// Metadata of the uploaded file, nil if there is no file
+	File *CallFile
+}
+
+// CallFile describes the file uploaded with the call
+type CallFile struct {
+	Name string
+
+	// Size of the file in bytes, -1 if unknown
+	Size int64
+}
+
+// CallResult is the result of the call to the API
+type CallResult struct {
+	// Raw json body of the response, it is decoded by the method which made the call
+	Body []byte
+
+	// Decoded OK flag and description of the response
+	Response Response
+}
+
+// CallHandler makes the call to the API
+type CallHandler func(ctx context.Context, call *Call) (*CallResult, error)
+
+// Middleware wraps CallHandler to run code before and after the call.
+// It can modify the call or the result and short-circuit the call by not calling next,
+// in this case the Body of the returned result must contain the json response of the API method.
+type Middleware func(next CallHandler) CallHandler
+
+// call passes the request through the middlewares and makes it
+func (c *Client) call(ctx context.Context, path string, params url.Values, upload *fileUpload) ([]byte, error) {
+	call := &Call{
+		Path:   path,
+		Params: params,
+		Header: http.Header{},
+	}
+	if upload != nil {
+		call.File = &CallFile{Name: upload.name, Size: upload.size}
+	}
+
+	handler := func(ctx context.Context, call *Call) (*CallResult, error) {
+		body, err := c.doWithRetry(ctx, call, upload)
+		return newCallResult(body, err), err
+	}
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	handler = c.traceCall(handler)
+
+	result, err := handler(ctx, call)
+	if result == nil {
+		return nil, err
+	}
+
+	return result.Body, err
+}
+
+func newCallResult(body []byte, err error) *CallResult {
+	result := &CallResult{Body: body}
+
+	apiErr := &APIError{}
+	switch {
+	case err == nil:
+		result.Response.OK = true
+	case errors.As(err, &apiErr):
+		result.Response.Description = apiErr.Description
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var header, text string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Trace-Id")
+		text = r.URL.Query().Get("text")
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	var order []string
+	var calls []*Call
+	var results []*CallResult
+	record := func(name string) Middleware {
+		return func(next CallHandler) CallHandler {
+			return func(ctx context.Context, call *Call) (*CallResult, error) {
+				order = append(order, name)
+				result, err := next(ctx, call)
+				order = append(order, name)
+				return result, err
+			}
+		}
+	}
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		middlewares: []Middleware{
+			record("outer"),
+			func(next CallHandler) CallHandler {
+				return func(ctx context.Context, call *Call) (*CallResult, error) {
+					call.Header.Set("X-Trace-Id", "trace_1")
+					call.Params.Set("text", strings.ToUpper(call.Params.Get("text")))
+					result, err := next(ctx, call)
+					calls = append(calls, call)
+					results = append(results, result)
+					return result, err
+				}
+			},
+			record("inner"),
+		},
+	}
+
+	message := &Message{client: &client, Chat: Chat{ID: "id_1234"}, Text: "hello"}
+	require.NoError(message.Send())
+
+	assert.Equal([]string{"outer", "inner", "inner", "outer"}, order)
+	assert.Equal("trace_1", header)
+	assert.Equal("HELLO", text)
+
+	require.Len(calls, 1)
+	assert.Equal("/messages/sendText", calls[0].Path)
+	assert.Equal("id_1234", calls[0].Params.Get("chatId"))
+	assert.False(calls[0].Params.Has("token"))
+	assert.Nil(calls[0].File)
+	assert.True(results[0].Response.OK)
+}
+
+func TestClient_Middleware_File(t *testing.T) {
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	var file *CallFile
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		middlewares: []Middleware{func(next CallHandler) CallHandler {
+			return func(ctx context.Context, call *Call) (*CallResult, error) {
+				file = call.File
+				return next(ctx, call)
+			}
+		}},
+	}
+
+	message := &Message{client: &client, Chat: Chat{ID: "id_1234"}}
+	message.AttachNewReader("report.txt", strings.NewReader("report data"))
+
+	require.NoError(t, message.Send())
+	require.NotNil(t, file)
+	assert.Equal(t, CallFile{Name: "report.txt", Size: 11}, *file)
+}
+
+func TestClient_Middleware_ShortCircuit(t *testing.T) {
+	assert := assert.New(t)
+
+	errInjected := errors.New("injected fault")
+	client := Client{
+		baseURL: "http://127.0.0.1:0",
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		middlewares: []Middleware{func(next CallHandler) CallHandler {
+			return func(ctx context.Context, call *Call) (*CallResult, error) {
+				if call.Path == "/messages/sendText" {
+					return nil, errInjected
+				}
+				return &CallResult{Body: []byte(`{"ok":true,"firstName":"cached"}`), Response: Response{OK: true}}, nil
+			}
+		}},
+	}
+
+	_, err := client.Do("/messages/sendText", url.Values{}, nil)
+	assert.ErrorIs(err, errInjected)
+
+	info, err := client.GetChatInfo("id_1234")
+	if assert.NoError(err) {
+		assert.Equal("cached", info.FirstName)
+	}
+}
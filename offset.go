@@ -0,0 +1,106 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OffsetStore keeps the id of the last delivered event, so the updater resumes
+// from it after restart instead of losing or repeating events
+type OffsetStore interface {
+	// Load returns the saved event id, 0 if nothing is saved yet
+	Load(ctx context.Context) (int, error)
+
+	// Save stores the id of the last delivered event
+	Save(ctx context.Context, eventID int) error
+}
+
+// MemoryOffsetStore keeps the event id in memory, e.g. to share it between updaters
+type MemoryOffsetStore struct {
+	mu      sync.Mutex
+	eventID int
+}
+
+func (s *MemoryOffsetStore) Load(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventID, nil
+}
+
+func (s *MemoryOffsetStore) Save(ctx context.Context, eventID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.eventID = eventID
+	return nil
+}
+
+// FileOffsetStore keeps the event id in the file.
+// The file is replaced atomically, so it is never left half-written.
+type FileOffsetStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileOffsetStore returns the store writing the event id to the file at path
+func NewFileOffsetStore(path string) *FileOffsetStore {
+	return &FileOffsetStore{path: path}
+}
+
+func (s *FileOffsetStore) Load(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot read offset file: %w", err)
+	}
+
+	eventID, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse offset file: %w", err)
+	}
+
+	return eventID, nil
+}
+
+func (s *FileOffsetStore) Save(ctx context.Context, eventID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create offset file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(strconv.Itoa(eventID)); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write offset file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot sync offset file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot close offset file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), s.path); err != nil {
+		return fmt.Errorf("cannot replace offset file: %w", err)
+	}
+
+	return nil
+}
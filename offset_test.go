@@ -0,0 +1,108 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOffsetStore(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	store := NewFileOffsetStore(filepath.Join(dir, "offset"))
+	ctx := context.Background()
+
+	eventID, err := store.Load(ctx)
+	require.NoError(err)
+	assert.Equal(0, eventID)
+
+	require.NoError(store.Save(ctx, 42))
+	require.NoError(store.Save(ctx, 43))
+
+	eventID, err = NewFileOffsetStore(filepath.Join(dir, "offset")).Load(ctx)
+	require.NoError(err)
+	assert.Equal(43, eventID)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(err)
+	assert.Len(files, 1)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "offset"), []byte("garbage"), 0o600))
+	_, err = store.Load(ctx)
+	assert.Error(err)
+}
+
+func TestUpdater_OffsetStore(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	lastEventIDs := make(chan string, 10)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventID := r.FormValue("lastEventId")
+		select {
+		case lastEventIDs <- lastEventID:
+		default:
+		}
+
+		if lastEventID != "5" {
+			<-r.Context().Done()
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	store := &countingOffsetStore{}
+	require.NoError(store.Save(context.Background(), 5))
+
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.offsets = store
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan Event)
+	go updater.RunUpdatesCheck(ctx, ch)
+
+	assert.Equal("5", <-lastEventIDs)
+
+	for i := 0; i < 8; i++ {
+		event := <-ch
+		assert.Equal(i+1, event.EventID)
+	}
+
+	assert.Eventually(func() bool {
+		eventID, _ := store.Load(context.Background())
+		return eventID == 8
+	}, time.Second, time.Millisecond)
+	assert.Equal("8", <-lastEventIDs)
+	// the initial offset and one save for the whole batch
+	assert.EqualValues(2, atomic.LoadInt32(&store.saves))
+}
+
+type countingOffsetStore struct {
+	MemoryOffsetStore
+	saves int32
+}
+
+func (s *countingOffsetStore) Save(ctx context.Context, eventID int) error {
+	atomic.AddInt32(&s.saves, 1)
+	return s.MemoryOffsetStore.Save(ctx, eventID)
+}
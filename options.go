@@ -1,12 +1,114 @@
 package botgolang
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
 
+// ErrInvalidOption is returned by NewBot for unknown options and conflicting options
+var ErrInvalidOption = errors.New("invalid bot option")
+
+// BotOption configures the bot in NewBot.
+// Options are created by the Bot* functions, e.g. BotLogger or BotPollTime.
+// The Type and Value pair is kept for options implemented outside of the library,
+// NewBot adapts them by the type name.
 type BotOption interface {
 	Type() string
 	Value() interface{}
 }
 
+// Option is a functional BotOption changing the bot settings
+type Option func(*botConfig)
+
+func (o Option) Type() string {
+	return "option"
+}
+
+func (o Option) Value() interface{} {
+	return o
+}
+
+// botConfig holds the settings of NewBot
+type botConfig struct {
+	apiURL         string
+	debug          bool
+	client         *http.Client
+	sharedClient   bool
+	retryPolicy    RetryPolicy
+	rateLimiter    *RateLimiter
+	postForm       bool
+	postThreshold  int
+	redactPolicy   RedactPolicy
+	logger         Logger
+	middlewares    []Middleware
+	metrics        *Metrics
+	tracer         Tracer
+	pollTime       time.Duration
+	bufferSize     int
+	userAgent      string
+	requestTimeout time.Duration
+	uploadTimeout  time.Duration
+	skipGetInfo    bool
+	transport      *TransportConfig
+	tokenProvider  TokenProvider
+	offsetStore    OffsetStore
+	ackTimeout     time.Duration
+	ackMaxAttempts int
+	onAckGiveUp    func(Event)
+	pollPolicy     *PollPolicy
+	onUnknownType  func(UnknownType)
+
+	// the first invalid option
+	err error
+}
+
+func newBotConfig(opts []BotOption) (*botConfig, error) {
+	client := *http.DefaultClient
+	config := &botConfig{
+		apiURL: defaultAPIURL,
+		debug:  defaultDebug,
+		client: &client,
+		tracer: NoopTracer(),
+	}
+
+	for _, option := range opts {
+		adaptBotOption(option)(config)
+	}
+
+	if config.err == nil && config.sharedClient && config.transport != nil {
+		config.err = fmt.Errorf("%w: BotTransport cannot change the client passed to BotSharedHTTPClient, "+
+			"configure its transport with NewTransport instead", ErrInvalidOption)
+	}
+
+	return config, config.err
+}
+
+// adaptBotOption converts options of the Type and Value form to Option
+func adaptBotOption(option BotOption) Option {
+	if o, ok := option.(Option); ok {
+		return o
+	}
+
+	return func(config *botConfig) {
+		switch option.Type() {
+		case "api_url":
+			config.apiURL = option.Value().(string)
+		case "debug":
+			config.debug = option.Value().(bool)
+		case "http_client":
+			client := option.Value().(http.Client)
+			config.client = &client
+			config.sharedClient = false
+		default:
+			if config.err == nil {
+				config.err = fmt.Errorf("%w: unknown type %q", ErrInvalidOption, option.Type())
+			}
+		}
+	}
+}
+
 type BotApiURL string
 
 func (o BotApiURL) Type() string {
@@ -27,6 +129,25 @@ func (o BotDebug) Value() interface{} {
 	return bool(o)
 }
 
+// BotPostForm makes the client send params of all requests as
+// application/x-www-form-urlencoded POST body instead of GET query string.
+// It keeps the token and message texts out of proxy access logs.
+func BotPostForm(postForm bool) Option {
+	return func(config *botConfig) {
+		config.postForm = postForm
+	}
+}
+
+// BotPostThreshold makes the client send params as POST form body
+// only if the encoded params are longer than the given number of bytes.
+// Shorter requests are sent as GET.
+func BotPostThreshold(threshold int) Option {
+	return func(config *botConfig) {
+		config.postThreshold = threshold
+	}
+}
+
+// BotHTTPClient sets the copy of the http client, use BotSharedHTTPClient to share the client itself
 type BotHTTPClient http.Client
 
 func (o BotHTTPClient) Type() string {
@@ -36,3 +157,194 @@ func (o BotHTTPClient) Type() string {
 func (o BotHTTPClient) Value() interface{} {
 	return http.Client(o)
 }
+
+// BotRetryPolicy sets the policy for repeating failed API requests, e.g. NewExponentialBackoff().
+// By default failed requests are not repeated.
+func BotRetryPolicy(policy RetryPolicy) Option {
+	return func(config *botConfig) {
+		config.retryPolicy = policy
+	}
+}
+
+// BotRateLimiter sets the client-side rate limiter for API requests, see NewRateLimiter.
+// Keep the reference to the limiter to read its Stats.
+func BotRateLimiter(limiter *RateLimiter) Option {
+	return func(config *botConfig) {
+		config.rateLimiter = limiter
+	}
+}
+
+// BotRateLimits creates the client-side rate limiter with the given limits
+func BotRateLimits(limit RateLimit) Option {
+	return func(config *botConfig) {
+		config.rateLimiter = NewRateLimiter(limit)
+	}
+}
+
+// BotRedactPolicy sets how message texts, file names and user ids are written to the debug log.
+// The token is masked in any case.
+func BotRedactPolicy(policy RedactPolicy) Option {
+	return func(config *botConfig) {
+		config.redactPolicy = policy
+	}
+}
+
+// BotLogger sets the logger, e.g. NewSlogLogger(slog.Default()).
+// BotDebug has no effect with a custom logger, configure its level instead.
+func BotLogger(logger Logger) Option {
+	return func(config *botConfig) {
+		config.logger = logger
+	}
+}
+
+// BotMiddleware adds middlewares around every API call.
+// The first middleware is the outermost one, it sees the call first and the result last.
+func BotMiddleware(middlewares ...Middleware) Option {
+	return func(config *botConfig) {
+		config.middlewares = append(config.middlewares, middlewares...)
+	}
+}
+
+// BotMetrics collects metrics of API calls and the update loop.
+// The metrics middleware is the innermost one, so it measures the calls which reached the API.
+func BotMetrics(metrics *Metrics) Option {
+	return func(config *botConfig) {
+		config.metrics = metrics
+	}
+}
+
+// BotTracer traces API calls and processing of events received by the updater
+func BotTracer(tracer Tracer) Option {
+	return func(config *botConfig) {
+		config.tracer = tracer
+	}
+}
+
+// BotSharedHTTPClient makes the bot use the http client without copying it,
+// so its transport and connection pool are shared with the rest of the application.
+// It cannot be combined with BotTransport, which would change the shared client.
+func BotSharedHTTPClient(client *http.Client) Option {
+	return func(config *botConfig) {
+		config.client = client
+		config.sharedClient = true
+	}
+}
+
+// BotPollTime sets how long the API holds the request for new events, 60 seconds by default.
+// It is rounded up to whole seconds. If the timeout of the http client is not longer than the poll time,
+// NewBot logs a warning and lowers the poll time to the half of the timeout,
+// use BotRequestTimeout to limit requests instead.
+func BotPollTime(pollTime time.Duration) Option {
+	return func(config *botConfig) {
+		config.pollTime = pollTime
+	}
+}
+
+// BotUpdatesBufferSize sets the buffer size of the channel returned by GetUpdatesChannel.
+// The channel is unbuffered by default.
+func BotUpdatesBufferSize(size int) Option {
+	return func(config *botConfig) {
+		config.bufferSize = size
+	}
+}
+
+// BotUserAgent sets the User-Agent header of API requests
+func BotUserAgent(userAgent string) Option {
+	return func(config *botConfig) {
+		config.userAgent = userAgent
+	}
+}
+
+// BotRequestTimeout limits every attempt of an API request.
+// The poll time is added to the timeout of requests for events.
+// Uploads are limited by BotUploadTimeout instead.
+func BotRequestTimeout(timeout time.Duration) Option {
+	return func(config *botConfig) {
+		config.requestTimeout = timeout
+	}
+}
+
+// BotUploadTimeout limits every attempt of a file upload
+func BotUploadTimeout(timeout time.Duration) Option {
+	return func(config *botConfig) {
+		config.uploadTimeout = timeout
+	}
+}
+
+// BotSkipGetInfo makes NewBot construct the bot offline, without the request for the bot info.
+// Bot.Info is nil until it is loaded by Bot.Self or Bot.Refresh,
+// the token is not checked until the first request, use Bot.Ping for that.
+func BotSkipGetInfo(skip bool) Option {
+	return func(config *botConfig) {
+		config.skipGetInfo = skip
+	}
+}
+
+// BotTransport configures the proxy, TLS and connection pool of the http client, see TransportConfig.
+// The transport is set on the copy of the client, NewBot fails if it is combined with BotSharedHTTPClient
+// or if the proxy url or certificates are invalid.
+func BotTransport(transport TransportConfig) Option {
+	return func(config *botConfig) {
+		config.transport = &transport
+	}
+}
+
+// BotTokenProvider makes the bot read the token from the provider before every request,
+// e.g. EnvToken or NewFileToken, so the token can be rotated without restart.
+// The token passed to NewBot is ignored.
+func BotTokenProvider(provider TokenProvider) Option {
+	return func(config *botConfig) {
+		config.tokenProvider = provider
+	}
+}
+
+// BotOffsetStore makes the updater load the id of the last delivered event on start
+// and save the id of the last delivered event after every batch of events, e.g. NewFileOffsetStore("offset")
+func BotOffsetStore(store OffsetStore) Option {
+	return func(config *botConfig) {
+		config.offsetStore = store
+	}
+}
+
+// BotAckTimeout enables at-least-once delivery of events. Every event must be acked with Event.Ack
+// after processing, the offset is saved to the OffsetStore only for the acked events.
+// Events which are not acked in timeout are delivered again, see BotAckMaxAttempts.
+func BotAckTimeout(timeout time.Duration) Option {
+	return func(config *botConfig) {
+		config.ackTimeout = timeout
+	}
+}
+
+// BotAckMaxAttempts limits the number of deliveries of an event which is not acked, 5 by default.
+// After the last attempt times out the event is given up, see BotAckGiveUpHook.
+func BotAckMaxAttempts(attempts int) Option {
+	return func(config *botConfig) {
+		config.ackMaxAttempts = attempts
+	}
+}
+
+// BotAckGiveUpHook sets the function called for events which were not acked in all attempts,
+// e.g. to store them to a dead letter queue. Such events are logged by default.
+// The offset is committed past the given up events, they are not delivered again.
+func BotAckGiveUpHook(hook func(Event)) Option {
+	return func(config *botConfig) {
+		config.onAckGiveUp = hook
+	}
+}
+
+// BotPollPolicy sets the backoff between failed polls of events, the stall timeout
+// and callbacks of outages and recoveries, zero fields are set to defaults
+func BotPollPolicy(policy PollPolicy) Option {
+	return func(config *botConfig) {
+		config.pollPolicy = &policy
+	}
+}
+
+// BotUnknownTypeHook sets the function called for received events and parts of types unknown
+// to the library and not registered with RegisterEventType or RegisterPartType.
+// Use it to detect changes of the API. By default they are logged once per type.
+func BotUnknownTypeHook(hook func(UnknownType)) Option {
+	return func(config *botConfig) {
+		config.onUnknownType = hook
+	}
+}
@@ -0,0 +1,177 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customAPIURL string
+
+func (o customAPIURL) Type() string {
+	return "api_url"
+}
+
+func (o customAPIURL) Value() interface{} {
+	return string(o)
+}
+
+func TestNewBot_Options(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var userAgent atomic.Value
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.UserAgent())
+		_, _ = w.Write([]byte(`{"ok":true,"userId":"test_bot","nick":"test_bot"}`))
+	}))
+	defer func() { testServer.Close() }()
+
+	httpClient := &http.Client{}
+	logger := NewLogrusLogger(&logrus.Logger{})
+	bot, err := NewBot("test_token",
+		customAPIURL(testServer.URL),
+		BotPostThreshold(2048),
+		BotSharedHTTPClient(httpClient),
+		BotLogger(logger),
+		BotPollTime(1500*time.Millisecond),
+		BotUpdatesBufferSize(10),
+		BotUserAgent("test-bot/1.0"),
+		BotRequestTimeout(time.Second),
+		BotRateLimits(RateLimit{Rate: 100}),
+	)
+	require.NoError(err)
+	require.NotNil(bot.Info)
+	assert.Equal("test_bot", bot.Info.ID)
+
+	assert.Equal(testServer.URL, bot.client.baseURL)
+	assert.Equal(2048, bot.client.postThreshold)
+	assert.Same(httpClient, bot.client.client)
+	assert.Same(logger, bot.logger)
+	assert.Equal(2, bot.updater.PollTime)
+	assert.Equal(time.Second, bot.client.requestTimeout)
+	assert.NotNil(bot.client.rateLimiter)
+	assert.Equal("test-bot/1.0", userAgent.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Equal(10, cap(bot.GetUpdatesChannel(ctx)))
+}
+
+func TestNewBot_SkipGetInfo(t *testing.T) {
+	bot, err := NewBot("test_token", BotApiURL("http://127.0.0.1:0"), BotSkipGetInfo(true))
+	require.NoError(t, err)
+	assert.Nil(t, bot.Info)
+}
+
+func TestNewBot_HTTPClientCopy(t *testing.T) {
+	httpClient := http.Client{Timeout: time.Minute}
+	bot, err := NewBot("test_token", BotHTTPClient(httpClient), BotPollTime(30*time.Second), BotSkipGetInfo(true))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, bot.client.client.Timeout)
+	assert.NotSame(t, &httpClient, bot.client.client)
+}
+
+type unknownOption struct{}
+
+func (o unknownOption) Type() string {
+	return "api_uri"
+}
+
+func (o unknownOption) Value() interface{} {
+	return "http://127.0.0.1:0"
+}
+
+func TestNewBot_InvalidOptions(t *testing.T) {
+	_, err := NewBot("test_token", unknownOption{}, BotSkipGetInfo(true))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), "api_uri")
+
+	_, err = NewBot("test_token",
+		BotSharedHTTPClient(&http.Client{}),
+		BotTransport(TransportConfig{}),
+		BotSkipGetInfo(true),
+	)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	bot, err := NewBot("test_token",
+		BotSharedHTTPClient(&http.Client{}),
+		BotHTTPClient(http.Client{}),
+		BotTransport(TransportConfig{}),
+		BotPostForm(true),
+		BotSkipGetInfo(true),
+	)
+	require.NoError(t, err)
+	assert.True(t, bot.client.postForm)
+}
+
+func TestNewBot_PollTimeClientTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []BotOption
+		pollTime int
+	}{
+		{"legacy_client", []BotOption{BotHTTPClient(http.Client{Timeout: 30 * time.Second})}, 15},
+		{"shared_client", []BotOption{
+			BotSharedHTTPClient(&http.Client{Timeout: 30 * time.Second}),
+			BotPollTime(30 * time.Second),
+		}, 15},
+		{"short_timeout", []BotOption{BotHTTPClient(http.Client{Timeout: time.Second})}, 1},
+		{"longer_timeout", []BotOption{
+			BotHTTPClient(http.Client{Timeout: 30 * time.Second}),
+			BotPollTime(20 * time.Second),
+		}, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, err := NewBot("test_token", append(tt.opts, BotSkipGetInfo(true), BotLogger(NewLogrusLogger(&logrus.Logger{})))...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.pollTime, bot.updater.PollTime)
+		})
+	}
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL:        testServer.URL,
+		token:          "test_token",
+		client:         http.DefaultClient,
+		logger:         NewLogrusLogger(&logrus.Logger{}),
+		requestTimeout: 10 * time.Millisecond,
+	}
+
+	_, err := client.Do("/chats/getInfo", url.Values{}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrTransport)
+
+	ctx, cancel := client.attemptContext(context.Background(), &Call{
+		Path:   "/events/get",
+		Params: url.Values{"pollTime": {"60"}},
+	}, nil)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Greater(t, time.Until(deadline), time.Minute)
+}
+
+func TestPollTimeSeconds(t *testing.T) {
+	assert.Equal(t, 0, pollTimeSeconds(0))
+	assert.Equal(t, 1, pollTimeSeconds(time.Millisecond))
+	assert.Equal(t, 30, pollTimeSeconds(30*time.Second))
+}
@@ -0,0 +1,254 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoClient is returned by FilePart.File and VoicePart.File for parts not received by the bot
+var ErrNoClient = errors.New("part is not bound to the bot")
+
+// StickerPart is the sticker sent in the message
+type StickerPart struct {
+	// Id of the sticker file
+	FileID string
+}
+
+// MentionPart is the user mentioned in the message
+type MentionPart struct {
+	User Contact
+}
+
+// VoicePart is the voice message
+type VoicePart struct {
+	lazyFile
+
+	// Id of the voice file
+	FileID string
+}
+
+// FilePart is the file attached to the message
+type FilePart struct {
+	lazyFile
+
+	// Id of the file
+	FileID string
+
+	// Type of the file, e.g. image, video or audio
+	Type string
+
+	// Caption of the file
+	Caption string
+}
+
+// ForwardPart is the forwarded message
+type ForwardPart struct {
+	// The forwarded message with its original chat
+	Message *Message
+
+	// Author of the forwarded message
+	From Contact
+
+	// Parts of the forwarded message
+	Parts []Part
+}
+
+// ReplyPart is the message quoted by the reply
+type ReplyPart struct {
+	// The quoted message
+	Message *Message
+
+	// Author of the quoted message
+	From Contact
+
+	// Parts of the quoted message
+	Parts []Part
+}
+
+func (p *StickerPart) PartType() PartType { return STICKER }
+func (p *MentionPart) PartType() PartType { return MENTION }
+func (p *VoicePart) PartType() PartType   { return VOICE }
+func (p *FilePart) PartType() PartType    { return FILE }
+func (p *ForwardPart) PartType() PartType { return FORWARD }
+func (p *ReplyPart) PartType() PartType   { return REPLY }
+
+// File returns the info of the voice file, it is loaded on the first call
+func (p *VoicePart) File(ctx context.Context) (*File, error) {
+	return p.load(func(c *Client) (*File, error) {
+		return c.GetVoiceInfoWithContext(ctx, p.FileID)
+	})
+}
+
+// File returns the info of the file with its size, name and url, it is loaded on the first call
+func (p *FilePart) File(ctx context.Context) (*File, error) {
+	return p.load(func(c *Client) (*File, error) {
+		return c.GetFileInfoWithContext(ctx, p.FileID)
+	})
+}
+
+// lazyFile loads the file info once it is successfully requested
+type lazyFile struct {
+	client *Client
+
+	mu   sync.Mutex
+	file *File
+}
+
+func (f *lazyFile) load(get func(c *Client) (*File, error)) (*File, error) {
+	if f.client == nil {
+		return nil, ErrNoClient
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file != nil {
+		return f.file, nil
+	}
+
+	file, err := get(f.client)
+	if err != nil {
+		return nil, err
+	}
+
+	f.file = file
+	return file, nil
+}
+
+// builtin decodes the part of the known type, it returns nil for unknown types
+func (p *Part) builtin() TypedPart {
+	payload := &p.Payload
+
+	switch p.Type {
+	case STICKER:
+		return &StickerPart{FileID: payload.FileID}
+	case MENTION:
+		return &MentionPart{User: Contact{
+			User:      User{ID: payload.UserID},
+			FirstName: payload.FirstName,
+			LastName:  payload.LastName,
+		}}
+	case VOICE:
+		return &VoicePart{lazyFile: lazyFile{client: p.client}, FileID: payload.FileID}
+	case FILE:
+		return &FilePart{
+			lazyFile: lazyFile{client: p.client},
+			FileID:   payload.FileID,
+			Type:     payload.Type,
+			Caption:  payload.Caption,
+		}
+	case FORWARD:
+		return &ForwardPart{
+			Message: partMessage(p.client, payload.PartMessage),
+			From:    payload.PartMessage.From,
+			Parts:   payload.PartMessage.Parts,
+		}
+	case REPLY:
+		return &ReplyPart{
+			Message: partMessage(p.client, payload.PartMessage),
+			From:    payload.PartMessage.From,
+			Parts:   payload.PartMessage.Parts,
+		}
+	}
+
+	return nil
+}
+
+func partMessage(client *Client, msg PartMessage) *Message {
+	msg.Chat.client = client
+	return &Message{
+		client:    client,
+		ID:        msg.MsgID,
+		Text:      msg.Text,
+		Chat:      msg.Chat,
+		Timestamp: msg.Timestamp,
+	}
+}
+
+// FileAttachment is the part carrying a file which can be downloaded, e.g. *FilePart or *VoicePart.
+// Parts decoded by decoders of RegisterPartType are attachments if they implement it.
+type FileAttachment interface {
+	TypedPart
+	File(ctx context.Context) (*File, error)
+}
+
+// Files returns the files attached to the message in the order of parts, voice messages included.
+// Use a type switch on *FilePart and *VoicePart to tell them apart.
+// Like the other accessors of parts, it decodes them by Part.Typed, so registered decoders are respected.
+func (ep *EventPayload) Files() []FileAttachment {
+	var files []FileAttachment
+	for _, typed := range ep.typedParts() {
+		if file, ok := typed.(FileAttachment); ok {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// ReplyTo returns the message quoted by the reply, nil if the message is not a reply.
+// The quoted message is in the chat of the event.
+// Reply parts decoded by a decoder of RegisterPartType into another type are skipped.
+func (ep *EventPayload) ReplyTo() *ReplyPart {
+	for _, typed := range ep.typedParts() {
+		if reply, ok := typed.(*ReplyPart); ok {
+			if reply.Message != nil && reply.Message.Chat.ID == "" {
+				reply.Message.Chat = ep.Chat
+				reply.Message.Chat.client = ep.client
+			}
+			return reply
+		}
+	}
+	return nil
+}
+
+// Forwards returns the forwarded messages with their original chats.
+// Forward parts decoded by a decoder of RegisterPartType into another type are skipped.
+func (ep *EventPayload) Forwards() []*ForwardPart {
+	var forwards []*ForwardPart
+	for _, typed := range ep.typedParts() {
+		if forward, ok := typed.(*ForwardPart); ok {
+			forwards = append(forwards, forward)
+		}
+	}
+	return forwards
+}
+
+// Mentions returns the users mentioned in the message.
+// Mention parts decoded by a decoder of RegisterPartType into another type are skipped.
+func (ep *EventPayload) Mentions() []*MentionPart {
+	var mentions []*MentionPart
+	for _, typed := range ep.typedParts() {
+		if mention, ok := typed.(*MentionPart); ok {
+			mentions = append(mentions, mention)
+		}
+	}
+	return mentions
+}
+
+// typedParts decodes the parts bound to the client of the payload
+func (ep *EventPayload) typedParts() []TypedPart {
+	typed := make([]TypedPart, 0, len(ep.Parts))
+	for _, part := range ep.Parts {
+		if part.client == nil {
+			part.client = ep.client
+		}
+		typed = append(typed, part.Typed())
+	}
+	return typed
+}
+
+// bind sets the client used by the helpers of the event, its payload and parts
+func (e *Event) bind(client *Client) {
+	e.client = client
+	e.Payload.client = client
+	bindParts(e.Payload.Parts, client)
+}
+
+func bindParts(parts []Part, client *Client) {
+	for i := range parts {
+		parts[i].client = client
+		bindParts(parts[i].Payload.PartMessage.Parts, client)
+		bindParts(parts[i].Payload.Message.Parts, client)
+	}
+}
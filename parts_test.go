@@ -0,0 +1,185 @@
+package botgolang
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const richPartsEvent = `{
+	"eventId": 1,
+	"type": "newMessage",
+	"payload": {
+		"msgId": "100",
+		"chat": {"chatId": "group@chat.agent", "type": "group"},
+		"from": {"userId": "author"},
+		"text": "see",
+		"parts": [
+			{"type": "mention", "payload": {"userId": "friend", "firstName": "Friend", "lastName": "Name"}},
+			{"type": "file", "payload": {"fileId": "file1", "type": "image", "caption": "Trip"}},
+			{"type": "sticker", "payload": {"fileId": "sticker1"}},
+			{"type": "voice", "payload": {"fileId": "voice1"}},
+			{"type": "forward", "payload": {"message": {
+				"msgId": "50",
+				"text": "forwarded",
+				"timestamp": 1546290000,
+				"chat": {"chatId": "channel@chat.agent", "type": "channel"},
+				"from": {"userId": "origin", "firstName": "Origin"}
+			}}},
+			{"type": "reply", "payload": {"message": {
+				"msgId": "99",
+				"text": "quoted",
+				"from": {"userId": "quoted", "firstName": "Quoted"},
+				"parts": [{"type": "file", "payload": {"fileId": "file2"}}]
+			}}}
+		]
+	}
+}`
+
+func TestEventPayload_Parts(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var infoCalls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("fileId") {
+		case "file1":
+			atomic.AddInt32(&infoCalls, 1)
+			_, _ = w.Write([]byte(`{"ok": true, "type": "image", "size": 1024, "filename": "trip.jpg", "url": "https://example.com/trip.jpg"}`))
+		case "voice1":
+			_, _ = w.Write([]byte(`{"ok": true, "type": "audio", "size": 2048}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		}
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  &http.Client{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+
+	event := Event{}
+	require.NoError(json.Unmarshal([]byte(richPartsEvent), &event))
+	event.bind(client)
+	payload := &event.Payload
+
+	mentions := payload.Mentions()
+	require.Len(mentions, 1)
+	assert.Equal(Contact{User: User{ID: "friend"}, FirstName: "Friend", LastName: "Name"}, mentions[0].User)
+
+	files := payload.Files()
+	require.Len(files, 2)
+	filePart, ok := files[0].(*FilePart)
+	require.True(ok)
+	assert.Equal("file1", filePart.FileID)
+	assert.Equal("image", filePart.Type)
+	assert.Equal("Trip", filePart.Caption)
+	for i := 0; i < 2; i++ {
+		file, err := files[0].File(context.Background())
+		require.NoError(err)
+		assert.Equal("trip.jpg", file.Name)
+		assert.Equal(uint64(1024), file.Size)
+	}
+	assert.Equal(int32(1), atomic.LoadInt32(&infoCalls))
+
+	voicePart, ok := files[1].(*VoicePart)
+	require.True(ok)
+	assert.Equal("voice1", voicePart.FileID)
+	voiceFile, err := files[1].File(context.Background())
+	require.NoError(err)
+	assert.Equal(uint64(2048), voiceFile.Size)
+
+	forwards := payload.Forwards()
+	require.Len(forwards, 1)
+	assert.Equal("50", forwards[0].Message.ID)
+	assert.Equal("forwarded", forwards[0].Message.Text)
+	assert.Equal("channel@chat.agent", forwards[0].Message.Chat.ID)
+	assert.Equal(Channel, forwards[0].Message.Chat.Type)
+	assert.Equal("origin", forwards[0].From.ID)
+
+	reply := payload.ReplyTo()
+	require.NotNil(reply)
+	assert.Equal("99", reply.Message.ID)
+	assert.Equal("quoted", reply.Message.Text)
+	assert.Equal("group@chat.agent", reply.Message.Chat.ID)
+	assert.Equal("Quoted", reply.From.FirstName)
+	require.Len(reply.Parts, 1)
+	assert.Equal(payload.Parts[5].Payload.PartMessage, payload.Parts[5].Payload.Message)
+
+	quotedFile, ok := reply.Parts[0].Typed().(*FilePart)
+	require.True(ok)
+	_, err = quotedFile.File(context.Background())
+	assert.NoError(err)
+
+	sticker, ok := payload.Parts[2].Typed().(*StickerPart)
+	require.True(ok)
+	assert.Equal("sticker1", sticker.FileID)
+
+	voice, ok := payload.Parts[3].Typed().(*VoicePart)
+	require.True(ok)
+	assert.Equal("voice1", voice.FileID)
+
+	for _, part := range payload.Parts {
+		assert.Equal(part.Type, part.Typed().PartType())
+	}
+
+	assert.Nil((&EventPayload{}).ReplyTo())
+	assert.Empty((&EventPayload{}).Files())
+
+	_, err = (&FilePart{FileID: "file1"}).File(context.Background())
+	assert.ErrorIs(err, ErrNoClient)
+}
+
+type documentPart struct {
+	FilePart
+}
+
+func (p *documentPart) PartType() PartType { return "testDocument" }
+
+func TestEventPayload_RegisteredFiles(t *testing.T) {
+	RegisterPartType("testDocument", func(part *Part) (TypedPart, error) {
+		return &documentPart{FilePart: FilePart{FileID: part.Payload.FileID}}, nil
+	})
+
+	event := Event{}
+	require.NoError(t, json.Unmarshal([]byte(`{"eventId": 1, "type": "newMessage", "payload": {"parts": [
+		{"type": "testDocument", "payload": {"fileId": "doc1"}},
+		{"type": "sticker", "payload": {"fileId": "sticker1"}}
+	]}}`), &event))
+
+	files := event.Payload.Files()
+	require.Len(t, files, 1)
+	document, ok := files[0].(*documentPart)
+	require.True(t, ok)
+	assert.Equal(t, "doc1", document.FileID)
+}
+
+func TestEventPayload_RegisteredBuiltinType(t *testing.T) {
+	RegisterPartType(MENTION, func(part *Part) (TypedPart, error) {
+		return &MentionPart{User: Contact{User: User{ID: "decoded " + part.Payload.UserID}}}, nil
+	})
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.parts, MENTION)
+	})
+
+	event := Event{}
+	require.NoError(t, json.Unmarshal([]byte(richPartsEvent), &event))
+
+	// the accessor uses the same decoder as Typed
+	mentions := event.Payload.Mentions()
+	require.Len(t, mentions, 1)
+	assert.Equal(t, "decoded friend", mentions[0].User.ID)
+	assert.Equal(t, mentions[0], event.Payload.Parts[0].Typed())
+}
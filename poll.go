@@ -0,0 +1,124 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	defaultPollInitialInterval = time.Second
+	defaultPollMaxInterval     = time.Minute
+	defaultPollStallTimeout    = 15 * time.Second
+	defaultPollOutageThreshold = 3
+)
+
+// ErrPollStalled is returned when the long poll of events got no response in time
+var ErrPollStalled = errors.New("long poll of events stalled")
+
+// PollPolicy defines how the updater handles failed polls of events
+type PollPolicy struct {
+	// Delays between failed polls, MaxAttempts and Idempotent are ignored.
+	// By default the delay grows from 1 second to 1 minute with 0.2 jitter.
+	Backoff *ExponentialBackoff
+
+	// Time to wait for the response over the poll time before the connection
+	// is considered stalled and dropped, 15 seconds by default
+	StallTimeout time.Duration
+
+	// Number of failed polls in a row which make an outage, 3 by default
+	OutageThreshold int
+
+	// OnOutage is called once the outage starts with the last error
+	OnOutage func(err error)
+
+	// OnRecovery is called on the first successful poll after the outage with its duration
+	OnRecovery func(outage time.Duration)
+}
+
+// DefaultPollPolicy returns PollPolicy with default settings
+func DefaultPollPolicy() PollPolicy {
+	return PollPolicy{}.withDefaults()
+}
+
+func (p PollPolicy) withDefaults() PollPolicy {
+	if p.Backoff == nil {
+		p.Backoff = &ExponentialBackoff{
+			InitialInterval: defaultPollInitialInterval,
+			MaxInterval:     defaultPollMaxInterval,
+			Multiplier:      defaultRetryMultiplier,
+			Jitter:          defaultRetryJitter,
+		}
+	}
+	if p.StallTimeout <= 0 {
+		p.StallTimeout = defaultPollStallTimeout
+	}
+	if p.OutageThreshold <= 0 {
+		p.OutageThreshold = defaultPollOutageThreshold
+	}
+	return p
+}
+
+// pollFailed counts the failed poll, reports the outage and returns the delay before the next poll
+func (u *Updater) pollFailed(err error) (time.Duration, int) {
+	now := time.Now()
+
+	u.mu.Lock()
+	u.failures++
+	failures := u.failures
+	if failures == 1 {
+		u.outageStart = now
+	}
+	u.lastError, u.lastErrorTime = err, now
+	u.mu.Unlock()
+
+	if failures == u.poll.OutageThreshold {
+		u.logger.Error("events are unavailable", LogFields{
+			"err":      err,
+			"failures": failures,
+		})
+		if u.poll.OnOutage != nil {
+			u.poll.OnOutage(err)
+		}
+	}
+
+	apiErr := &APIError{}
+	if !u.poll.Backoff.IgnoreRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, failures
+	}
+
+	return u.poll.Backoff.delay(failures), failures
+}
+
+// pollSucceeded resets the failures and reports the recovery after the outage
+func (u *Updater) pollSucceeded() {
+	u.mu.Lock()
+	failures, outage := u.failures, time.Since(u.outageStart)
+	u.failures = 0
+	u.mu.Unlock()
+
+	if failures >= u.poll.OutageThreshold {
+		u.logger.Info("events are available again", LogFields{
+			"failures": failures,
+			"outage":   outage,
+		})
+		if u.poll.OnRecovery != nil {
+			u.poll.OnRecovery(outage)
+		}
+	}
+}
+
+// sleepUntilStop waits for the delay, it returns false if ctx is done or stop is closed earlier
+func sleepUntilStop(ctx context.Context, stop <-chan struct{}, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,109 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPollTestUpdater(baseURL string, policy PollPolicy) *Updater {
+	client := &Client{
+		baseURL: baseURL,
+		token:   "test_token",
+		client:  &http.Client{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.poll = policy.withDefaults()
+	return updater
+}
+
+func TestUpdater_OutageAndRecovery(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch call := atomic.AddInt32(&calls, 1); {
+		case call <= 3:
+			w.WriteHeader(http.StatusBadGateway)
+		case call == 4:
+			(&MockHandler{}).ServeHTTP(w, r)
+		default:
+			<-r.Context().Done()
+		}
+	}))
+	defer func() { testServer.Close() }()
+
+	outages := make(chan error, 10)
+	recoveries := make(chan time.Duration, 10)
+	updater := newPollTestUpdater(testServer.URL, PollPolicy{
+		Backoff:         &ExponentialBackoff{InitialInterval: time.Millisecond, Multiplier: 2},
+		OutageThreshold: 2,
+		OnOutage:        func(err error) { outages <- err },
+		OnRecovery:      func(outage time.Duration) { recoveries <- outage },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan Event, 10)
+	go updater.RunUpdatesCheck(ctx, ch)
+
+	event := <-ch
+	assert.Equal(1, event.EventID)
+
+	require.Len(t, outages, 1)
+	assert.ErrorIs(<-outages, ErrServer)
+	require.Len(t, recoveries, 1)
+	assert.Greater(<-recoveries, time.Duration(0))
+}
+
+func TestUpdater_PollStalled(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer func() { testServer.Close() }()
+
+	updater := newPollTestUpdater(testServer.URL, PollPolicy{StallTimeout: 20 * time.Millisecond})
+
+	_, err := updater.GetLastEventsWithContext(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrPollStalled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = updater.GetLastEventsWithContext(ctx, 60)
+	assert.NotErrorIs(t, err, ErrPollStalled)
+}
+
+func TestUpdater_ShutdownDuringBackoff(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer func() { testServer.Close() }()
+
+	updater := newPollTestUpdater(testServer.URL, PollPolicy{
+		Backoff: &ExponentialBackoff{InitialInterval: time.Hour},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan Event)
+	go updater.RunUpdatesCheck(ctx, ch)
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("updater has not stopped")
+	}
+}
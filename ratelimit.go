@@ -0,0 +1,245 @@
+package botgolang
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultThrottlePause is used when the API reports throttling without Retry-After
+	defaultThrottlePause = time.Second
+
+	// idle chat buckets are removed when there are more of them than this number
+	maxIdleChatBuckets = 1024
+)
+
+// RateLimit configures RateLimiter.
+// Zero Rate or ChatRate disables the corresponding limit.
+type RateLimit struct {
+	// Requests per second for all API methods
+	Rate float64
+
+	// Max number of requests which can be made at once
+	Burst int
+
+	// Requests per second to a single chat
+	ChatRate float64
+
+	// Max number of requests to a single chat which can be made at once
+	ChatBurst int
+}
+
+// RateLimiterStats contains counters of RateLimiter
+type RateLimiterStats struct {
+	// Number of requests passed through the limiter
+	Requests uint64
+
+	// Number of requests which had to wait
+	Delayed uint64
+
+	// Number of throttling errors from the API
+	Throttled uint64
+
+	// Total time spent by callers waiting for the limiter
+	TotalWait time.Duration
+
+	// The longest wait of a single request
+	MaxWait time.Duration
+}
+
+// RateLimiter is a client-side token bucket limiter with a global budget and a budget per chat.
+// Callers block until the request is allowed or the context is done.
+// When the API reports throttling, the limiter pauses the corresponding bucket.
+type RateLimiter struct {
+	limit RateLimit
+
+	mu     sync.Mutex
+	global *tokenBucket
+	chats  map[string]*tokenBucket
+	stats  RateLimiterStats
+}
+
+// NewRateLimiter returns new RateLimiter
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	limiter := &RateLimiter{
+		limit: limit,
+		chats: make(map[string]*tokenBucket),
+	}
+
+	if limit.Rate > 0 {
+		limiter.global = newTokenBucket(limit.Rate, limit.Burst, time.Now())
+	}
+
+	return limiter
+}
+
+// Wait blocks until the request to the chat is allowed.
+// Empty chatID means the request is limited only by the global budget.
+func (l *RateLimiter) Wait(ctx context.Context, chatID string) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	buckets := l.buckets(chatID, now)
+	var delay time.Duration
+	for _, bucket := range buckets {
+		if d := bucket.reserve(now); d > delay {
+			delay = d
+		}
+	}
+	l.stats.Requests++
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		l.mu.Lock()
+		for _, bucket := range buckets {
+			bucket.cancel()
+		}
+		l.mu.Unlock()
+		return err
+	}
+
+	l.mu.Lock()
+	l.stats.Delayed++
+	l.stats.TotalWait += delay
+	if delay > l.stats.MaxWait {
+		l.stats.MaxWait = delay
+	}
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Throttled pauses the budget of the chat (or the global one for empty chatID)
+// after the API has rejected the request because of throttling
+func (l *RateLimiter) Throttled(chatID string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultThrottlePause
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Throttled++
+
+	bucket := l.global
+	if chatID != "" && l.limit.ChatRate > 0 {
+		bucket = l.chatBucket(chatID, now)
+	}
+	if bucket != nil {
+		bucket.pause(now, now.Add(retryAfter))
+	}
+}
+
+// Stats returns a snapshot of the limiter counters
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stats
+}
+
+func (l *RateLimiter) buckets(chatID string, now time.Time) []*tokenBucket {
+	buckets := make([]*tokenBucket, 0, 2)
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+	if chatID != "" && l.limit.ChatRate > 0 {
+		buckets = append(buckets, l.chatBucket(chatID, now))
+	}
+	return buckets
+}
+
+func (l *RateLimiter) chatBucket(chatID string, now time.Time) *tokenBucket {
+	bucket, ok := l.chats[chatID]
+	if ok {
+		return bucket
+	}
+
+	if len(l.chats) >= maxIdleChatBuckets {
+		for id, b := range l.chats {
+			if b.idle(now) {
+				delete(l.chats, id)
+			}
+		}
+	}
+
+	bucket = newTokenBucket(l.limit.ChatRate, l.limit.ChatBurst, now)
+	l.chats[chatID] = bucket
+	return bucket
+}
+
+// tokenBucket is not thread-safe, RateLimiter guards it with its mutex.
+// last may be in the future when the bucket is paused: tokens are not refilled until then.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns the time the caller has to wait for it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+
+	var delay time.Duration
+	if b.last.After(now) {
+		delay = b.last.Sub(now)
+	}
+	if b.tokens < 0 {
+		delay += time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+
+	return delay
+}
+
+// cancel returns the token taken by reserve
+func (b *tokenBucket) cancel() {
+	b.tokens++
+}
+
+// pause drains the bucket and stops refilling it until the given time
+func (b *tokenBucket) pause(now, until time.Time) {
+	b.refill(now)
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+	if until.After(b.last) {
+		b.last = until
+	}
+}
+
+func (b *tokenBucket) idle(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst && !b.last.After(now)
+}
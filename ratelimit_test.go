@@ -0,0 +1,59 @@
+package botgolang
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(RateLimit{
+		Rate:      1000,
+		Burst:     10,
+		ChatRate:  20,
+		ChatBurst: 1,
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+	require.NoError(limiter.Wait(ctx, "chat_1"))
+	require.NoError(limiter.Wait(ctx, "chat_2"))
+	assert.Less(time.Since(start), 40*time.Millisecond, "different chats should not wait for each other")
+
+	require.NoError(limiter.Wait(ctx, "chat_1"))
+	stats := limiter.Stats()
+	assert.EqualValues(3, stats.Requests)
+	assert.EqualValues(1, stats.Delayed)
+	assert.Greater(stats.TotalWait, 30*time.Millisecond)
+	assert.Equal(stats.TotalWait, stats.MaxWait)
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 1, Burst: 1})
+
+	require.NoError(t, limiter.Wait(context.Background(), ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx, ""), context.DeadlineExceeded)
+}
+
+func TestRateLimiter_Throttled(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{ChatRate: 1000, ChatBurst: 10})
+
+	limiter.Throttled("chat_1", 50*time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background(), "chat_2"))
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+
+	require.NoError(t, limiter.Wait(context.Background(), "chat_1"))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.EqualValues(t, 1, limiter.Stats().Throttled)
+}
@@ -0,0 +1,189 @@
+package botgolang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	redactedToken = "***"
+
+	defaultRedactTruncateLength = 16
+)
+
+// RedactMode defines how sensitive values are written to the debug log
+type RedactMode uint8
+
+const (
+	// RedactNone writes values as is
+	RedactNone RedactMode = iota
+
+	// RedactTruncate keeps only the beginning of the value and its length
+	RedactTruncate
+
+	// RedactHash replaces values with a short hash, so equal values can still be matched in logs
+	RedactHash
+
+	// RedactRemove replaces values with a placeholder
+	RedactRemove
+)
+
+// RedactPolicy defines how request params and response bodies are written to the debug log.
+// The token is always masked regardless of the policy.
+type RedactPolicy struct {
+	// Message texts and captions, chat titles, descriptions and rules
+	Text RedactMode
+
+	// Names of files
+	FileNames RedactMode
+
+	// Ids, nicks and names of users and chats
+	UserIDs RedactMode
+
+	// Number of characters kept by RedactTruncate, 16 by default
+	TruncateLength int
+}
+
+var (
+	redactTextKeys = map[string]bool{
+		"text":    true,
+		"caption": true,
+		"title":   true,
+		"about":   true,
+		"rules":   true,
+	}
+
+	redactFileNameKeys = map[string]bool{
+		"filename": true,
+		"fileName": true,
+	}
+
+	redactUserKeys = map[string]bool{
+		"userId":    true,
+		"chatId":    true,
+		"sn":        true,
+		"nick":      true,
+		"firstName": true,
+		"lastName":  true,
+		"members":   true,
+	}
+)
+
+func (p RedactPolicy) mode(key string) RedactMode {
+	switch {
+	case redactTextKeys[key]:
+		return p.Text
+	case redactFileNameKeys[key]:
+		return p.FileNames
+	case redactUserKeys[key]:
+		return p.UserIDs
+	}
+	return RedactNone
+}
+
+func (p RedactPolicy) redact(mode RedactMode, value string) string {
+	switch mode {
+	case RedactTruncate:
+		length := p.TruncateLength
+		if length <= 0 {
+			length = defaultRedactTruncateLength
+		}
+		runes := utf8.RuneCountInString(value)
+		if runes <= length {
+			return value
+		}
+		return string([]rune(value)[:length]) + "…(" + strconv.Itoa(runes) + ")"
+	case RedactHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	case RedactRemove:
+		return "[redacted]"
+	}
+	return value
+}
+
+// params returns a copy of request params with the token masked and sensitive values redacted
+func (p RedactPolicy) params(params url.Values) url.Values {
+	redacted := make(url.Values, len(params))
+	for key, values := range params {
+		mode := p.mode(key)
+		redactedValues := make([]string, len(values))
+		for i, value := range values {
+			redactedValues[i] = p.redact(mode, value)
+		}
+		redacted[key] = redactedValues
+	}
+
+	if redacted.Has("token") {
+		redacted.Set("token", redactedToken)
+	}
+
+	return redacted
+}
+
+// url returns the request url with redacted query
+func (p RedactPolicy) url(apiURL *url.URL) string {
+	redacted := *apiURL
+	if redacted.RawQuery != "" {
+		query, err := url.ParseQuery(redacted.RawQuery)
+		if err != nil {
+			// never log unparsable query, it may contain the token
+			redacted.RawQuery = redactedToken
+		} else {
+			redacted.RawQuery = p.params(query).Encode()
+		}
+	}
+	return redacted.String()
+}
+
+// body returns the json response body with sensitive values redacted
+func (p RedactPolicy) body(body []byte) string {
+	if p.Text == RedactNone && p.FileNames == RedactNone && p.UserIDs == RedactNone {
+		return string(body)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return p.redact(RedactRemove, "")
+	}
+
+	redacted, err := json.Marshal(p.value(RedactNone, data))
+	if err != nil {
+		return p.redact(RedactRemove, "")
+	}
+	return string(redacted)
+}
+
+func (p RedactPolicy) value(mode RedactMode, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = p.value(p.mode(key), item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = p.value(mode, item)
+		}
+	case string:
+		return p.redact(mode, v)
+	}
+	return value
+}
+
+// err masks the token in url errors returned by http client
+func (p RedactPolicy) err(err error) error {
+	urlErr := &url.Error{}
+	if errors.As(err, &urlErr) {
+		if parsed, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = p.url(parsed)
+		} else {
+			urlErr.URL = redactedToken
+		}
+	}
+	return err
+}
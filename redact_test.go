@@ -0,0 +1,97 @@
+package botgolang
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactPolicy_Params(t *testing.T) {
+	params := url.Values{
+		"token":  {"secret"},
+		"chatId": {"user@example.com"},
+		"text":   {"very private message text"},
+		"msgId":  {"123"},
+	}
+
+	tests := []struct {
+		name   string
+		policy RedactPolicy
+		want   url.Values
+	}{
+		{
+			name:   "token_only",
+			policy: RedactPolicy{},
+			want: url.Values{
+				"token":  {"***"},
+				"chatId": {"user@example.com"},
+				"text":   {"very private message text"},
+				"msgId":  {"123"},
+			},
+		},
+		{
+			name:   "all",
+			policy: RedactPolicy{Text: RedactTruncate, UserIDs: RedactRemove, TruncateLength: 4},
+			want: url.Values{
+				"token":  {"***"},
+				"chatId": {"[redacted]"},
+				"text":   {"very…(25)"},
+				"msgId":  {"123"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.params(params))
+		})
+	}
+
+	assert.Equal(t, "secret", params.Get("token"), "params must not be modified")
+}
+
+func TestRedactPolicy_Body(t *testing.T) {
+	policy := RedactPolicy{Text: RedactRemove, FileNames: RedactRemove, UserIDs: RedactHash}
+	body := `{"ok":true,"filename":"passport.jpg","from":{"userId":"1234"},"text":"hi","msgId":"5"}`
+
+	redacted := policy.body([]byte(body))
+
+	assert.JSONEq(t, `{"ok":true,"filename":"[redacted]","from":{"userId":"`+
+		policy.redact(RedactHash, "1234")+`"},"text":"[redacted]","msgId":"5"}`, redacted)
+	assert.Equal(t, body, RedactPolicy{}.body([]byte(body)))
+}
+
+func TestClient_Do_RedactLogs(t *testing.T) {
+	testServer := httptest.NewServer(&MockHandler{})
+	defer func() { testServer.Close() }()
+
+	output := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(output)
+	logger.SetLevel(logrus.DebugLevel)
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "secret_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(logger),
+		redact:  RedactPolicy{Text: RedactHash},
+	}
+
+	err := client.SendTextMessage(&Message{Chat: Chat{ID: "id_1234"}, Text: "private text"})
+	require.NoError(t, err)
+
+	testServer.Close()
+	_, err = client.Do("/self/get", url.Values{}, nil)
+	require.Error(t, err)
+
+	assert.NotContains(t, output.String(), "secret_token")
+	assert.NotContains(t, err.Error(), "secret_token")
+	assert.NotContains(t, output.String(), "private")
+	assert.Contains(t, output.String(), "id_1234")
+}
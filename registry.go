@@ -0,0 +1,270 @@
+package botgolang
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+const (
+	unknownKindEvent = "event"
+	unknownKindPart  = "part"
+)
+
+// EventDecoder decodes the event of the registered type, usually from its raw JSON
+type EventDecoder func(event *Event) (TypedEvent, error)
+
+// PartDecoder decodes the part of the registered type, usually from its raw JSON
+type PartDecoder func(part *Part) (TypedPart, error)
+
+// TypedPart is the part decoded by its type, see Part.Typed
+type TypedPart interface {
+	// PartType returns the type of the part
+	PartType() PartType
+}
+
+// UnknownPart is the part of the type without decoder
+type UnknownPart struct {
+	Part Part
+
+	// Error of the registered decoder, nil if there is no decoder
+	Err error
+}
+
+func (p *UnknownPart) PartType() PartType { return p.Part.Type }
+
+// UnknownType describes the event or the part of the type unknown to the library and not registered,
+// it usually means that the API has got new features
+type UnknownType struct {
+	// "event" or "part"
+	Kind string
+
+	// Type of the event or the part
+	Type string
+
+	// Id of the event with the unknown type or with the part of the unknown type
+	EventID int
+
+	// Raw JSON of the event or the part
+	Raw json.RawMessage
+}
+
+var registry = struct {
+	sync.RWMutex
+	events map[EventType]EventDecoder
+	parts  map[PartType]PartDecoder
+}{
+	events: map[EventType]EventDecoder{},
+	parts:  map[PartType]PartDecoder{},
+}
+
+// RegisterEventType registers the decoder used by Event.Typed for events of the type.
+// It replaces the built-in decoding of known types. Register types before receiving events.
+func RegisterEventType(eventType EventType, decoder EventDecoder) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.events[eventType] = decoder
+}
+
+// RegisterPartType registers the decoder used by Part.Typed for parts of the type.
+// Register types before receiving events.
+func RegisterPartType(partType PartType, decoder PartDecoder) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.parts[partType] = decoder
+}
+
+func eventDecoder(eventType EventType) EventDecoder {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.events[eventType]
+}
+
+func partDecoder(partType PartType) PartDecoder {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.parts[partType]
+}
+
+func knownEventType(eventType EventType) bool {
+	switch eventType {
+	case NEW_MESSAGE, EDITED_MESSAGE, DELETED_MESSAGE, PINNED_MESSAGE, UNPINNED_MESSAGE,
+		NEW_CHAT_MEMBERS, LEFT_CHAT_MEMBERS, CALLBACK_QUERY:
+		return true
+	}
+	return eventDecoder(eventType) != nil
+}
+
+func knownPartType(partType PartType) bool {
+	switch partType {
+	case STICKER, MENTION, VOICE, FILE, FORWARD, REPLY:
+		return true
+	}
+	return partDecoder(partType) != nil
+}
+
+// unknownTypes returns the unknown types of the event and its parts
+func unknownTypes(event *Event) []UnknownType {
+	var unknown []UnknownType
+	if !knownEventType(event.Type) {
+		unknown = append(unknown, UnknownType{
+			Kind:    unknownKindEvent,
+			Type:    string(event.Type),
+			EventID: event.EventID,
+			Raw:     event.RawJSON(),
+		})
+	}
+	return appendUnknownParts(unknown, event.EventID, event.Payload.Parts)
+}
+
+// appendUnknownParts collects parts of unknown types including the parts of replied and forwarded messages
+func appendUnknownParts(unknown []UnknownType, eventID int, parts []Part) []UnknownType {
+	for i := range parts {
+		part := &parts[i]
+		if !knownPartType(part.Type) {
+			unknown = append(unknown, UnknownType{
+				Kind:    unknownKindPart,
+				Type:    string(part.Type),
+				EventID: eventID,
+				Raw:     part.RawJSON(),
+			})
+		}
+		unknown = appendUnknownParts(unknown, eventID, part.Payload.Message.Parts)
+	}
+	return unknown
+}
+
+// Typed returns the part decoded by its type: *StickerPart, *MentionPart, *VoicePart, *FilePart,
+// *ForwardPart or *ReplyPart. Parts of types registered with RegisterPartType are decoded by their decoders,
+// parts of unknown types are returned as *UnknownPart.
+func (p *Part) Typed() TypedPart {
+	if decoder := partDecoder(p.Type); decoder != nil {
+		typed, err := decoder(p)
+		if err != nil {
+			return &UnknownPart{Part: *p, Err: err}
+		}
+		return typed
+	}
+
+	if typed := p.builtin(); typed != nil {
+		return typed
+	}
+	return &UnknownPart{Part: *p}
+}
+
+// RawJSON returns the JSON the event was decoded from, nil if the event was not decoded
+func (e *Event) RawJSON() json.RawMessage {
+	return e.raw
+}
+
+// RawJSON returns the JSON the payload was decoded from, nil if the payload was not decoded
+func (ep *EventPayload) RawJSON() json.RawMessage {
+	return ep.raw
+}
+
+// RawJSON returns the JSON the part was decoded from, nil if the part was not decoded
+func (p *Part) RawJSON() json.RawMessage {
+	return p.raw
+}
+
+// rawJSON fetches the next value and returns the lexer of its copy
+func rawJSON(l *jlexer.Lexer) ([]byte, *jlexer.Lexer) {
+	data := l.Raw()
+	if !l.Ok() {
+		return nil, nil
+	}
+	raw := make([]byte, len(data))
+	copy(raw, data)
+	return raw, &jlexer.Lexer{Data: raw}
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (e *Event) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	raw, in := rawJSON(l)
+	if in == nil {
+		return
+	}
+	(*eventJSON)(e).UnmarshalEasyJSON(in)
+	e.raw = raw
+	l.AddError(in.Error())
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (e *Event) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	e.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (e Event) MarshalEasyJSON(w *jwriter.Writer) {
+	eventJSON(e).MarshalEasyJSON(w)
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (e Event) MarshalJSON() ([]byte, error) {
+	return eventJSON(e).MarshalJSON()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (ep *EventPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	raw, in := rawJSON(l)
+	if in == nil {
+		return
+	}
+	(*eventPayloadJSON)(ep).UnmarshalEasyJSON(in)
+	ep.raw = raw
+	l.AddError(in.Error())
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (ep *EventPayload) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	ep.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (ep EventPayload) MarshalEasyJSON(w *jwriter.Writer) {
+	eventPayloadJSON(ep).MarshalEasyJSON(w)
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (ep EventPayload) MarshalJSON() ([]byte, error) {
+	return eventPayloadJSON(ep).MarshalJSON()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (p *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	raw, in := rawJSON(l)
+	if in == nil {
+		return
+	}
+	(*partJSON)(p).UnmarshalEasyJSON(in)
+	p.Payload.Message = p.Payload.PartMessage
+	p.raw = raw
+	l.AddError(in.Error())
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (p *Part) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	p.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (p Part) MarshalEasyJSON(w *jwriter.Writer) {
+	partJSON(p).MarshalEasyJSON(w)
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (p Part) MarshalJSON() ([]byte, error) {
+	return partJSON(p).MarshalJSON()
+}
@@ -0,0 +1,192 @@
+package botgolang
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const driftEvents = `{
+	"ok": true,
+	"events": [
+		{
+			"eventId": 1,
+			"type": "changedChatInfo",
+			"payload": {"chat": {"chatId": "group@chat.agent"}, "title": "New title"}
+		},
+		{
+			"eventId": 2,
+			"type": "newMessage",
+			"payload": {
+				"msgId": "1",
+				"text": "vote",
+				"parts": [{"type": "poll", "payload": {"question": "Lunch?", "answers": ["yes", "no"]}}]
+			}
+		}
+	]
+}`
+
+type chatInfoChangedEvent struct {
+	Title string `json:"title"`
+}
+
+func (e *chatInfoChangedEvent) EventType() EventType { return "testChangedChatInfo" }
+
+type pollPart struct {
+	Question string   `json:"question"`
+	Answers  []string `json:"answers"`
+}
+
+func (p *pollPart) PartType() PartType { return "testPoll" }
+
+func TestEvent_RawJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	events := mockEvents(t)
+
+	raw := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(events[0].RawJSON(), &raw))
+	assert.Equal(float64(1), raw["eventId"])
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(events[0].Payload.RawJSON(), &payload))
+	assert.Equal("Hello!", payload["text"])
+
+	part := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(events[0].Payload.Parts[3].RawJSON(), &part))
+	assert.Equal("file", part["type"])
+
+	data, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	decoded := Event{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(events[0].Payload.Text, decoded.Payload.Text)
+	assert.Len(decoded.Payload.Parts, 6)
+
+	assert.Nil((&Event{}).RawJSON())
+}
+
+func TestRegisterTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterEventType("testChangedChatInfo", func(event *Event) (TypedEvent, error) {
+		typed := &chatInfoChangedEvent{}
+		return typed, json.Unmarshal(event.Payload.RawJSON(), typed)
+	})
+	RegisterPartType("testPoll", func(part *Part) (TypedPart, error) {
+		typed := &pollPart{}
+		raw := struct {
+			Payload *pollPart `json:"payload"`
+		}{typed}
+		return typed, json.Unmarshal(part.RawJSON(), &raw)
+	})
+	errDecoder := errors.New("cannot decode")
+	RegisterEventType("testBroken", func(event *Event) (TypedEvent, error) {
+		return nil, errDecoder
+	})
+
+	event := Event{}
+	require.NoError(t, json.Unmarshal([]byte(`{"eventId": 1, "type": "testChangedChatInfo", "payload": {"title": "New title"}}`), &event))
+	chatInfo, ok := event.Typed().(*chatInfoChangedEvent)
+	if assert.True(ok) {
+		assert.Equal("New title", chatInfo.Title)
+	}
+
+	part := Part{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "testPoll", "payload": {"question": "Lunch?", "answers": ["yes", "no"]}}`), &part))
+	poll, ok := part.Typed().(*pollPart)
+	if assert.True(ok) {
+		assert.Equal("Lunch?", poll.Question)
+		assert.Equal([]string{"yes", "no"}, poll.Answers)
+	}
+
+	unknown, ok := (&Event{Type: "testBroken"}).Typed().(*UnknownEvent)
+	if assert.True(ok) {
+		assert.ErrorIs(unknown.Err, errDecoder)
+	}
+
+	unknownPart, ok := (&Part{Type: "testUnknown"}).Typed().(*UnknownPart)
+	if assert.True(ok) {
+		assert.NoError(unknownPart.Err)
+		assert.Equal(PartType("testUnknown"), unknownPart.PartType())
+	}
+}
+
+func TestUpdater_UnknownTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(driftEvents))
+	}))
+	defer func() { testServer.Close() }()
+
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  &http.Client{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+	updater.metrics = NewMetrics()
+
+	var unknown []UnknownType
+	updater.onUnknownType = func(u UnknownType) {
+		unknown = append(unknown, u)
+	}
+
+	events, err := updater.GetLastEventsWithContext(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	if assert.Len(unknown, 2) {
+		assert.Equal("event", unknown[0].Kind)
+		assert.Equal("changedChatInfo", unknown[0].Type)
+		assert.Equal(1, unknown[0].EventID)
+		assert.JSONEq(`{"chat": {"chatId": "group@chat.agent"}, "title": "New title"}`, string(events[0].Payload.RawJSON()))
+
+		assert.Equal("part", unknown[1].Kind)
+		assert.Equal("poll", unknown[1].Type)
+		assert.Equal(2, unknown[1].EventID)
+		assert.JSONEq(`{"type": "poll", "payload": {"question": "Lunch?", "answers": ["yes", "no"]}}`, string(unknown[1].Raw))
+	}
+
+	w := httptest.NewRecorder()
+	updater.metrics.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(w.Body.String(), `botgolang_unknown_types_total{kind="event",type="changedChatInfo"} 1`)
+	assert.Contains(w.Body.String(), `botgolang_unknown_types_total{kind="part",type="poll"} 1`)
+}
+
+func TestUnknownTypes_Nested(t *testing.T) {
+	assert := assert.New(t)
+
+	event := &Event{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"eventId": 3,
+		"type": "newMessage",
+		"payload": {
+			"msgId": "2",
+			"parts": [{
+				"type": "reply",
+				"payload": {"message": {"msgId": "1", "parts": [{
+					"type": "forward",
+					"payload": {"message": {"msgId": "0", "parts": [{"type": "poll", "payload": {}}]}}
+				}]}}
+			}]
+		}
+	}`), event))
+
+	unknown := unknownTypes(event)
+	if assert.Len(unknown, 1) {
+		assert.Equal("part", unknown[0].Kind)
+		assert.Equal("poll", unknown[0].Type)
+		assert.Equal(3, unknown[0].EventID)
+		assert.JSONEq(`{"type": "poll", "payload": {}}`, string(unknown[0].Raw))
+	}
+}
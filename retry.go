@@ -0,0 +1,178 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts     = 3
+	defaultRetryInitialInterval = 500 * time.Millisecond
+	defaultRetryMaxInterval     = 10 * time.Second
+	defaultRetryMultiplier      = 2
+	defaultRetryJitter          = 0.2
+)
+
+// idempotentPaths contains API methods which can be repeated without side effects
+var idempotentPaths = map[string]bool{
+	"/self/get":                true,
+	"/events/get":              true,
+	"/chats/getInfo":           true,
+	"/chats/getAdmins":         true,
+	"/chats/getMembers":        true,
+	"/chats/getBlockedUsers":   true,
+	"/chats/getPendingUsers":   true,
+	"/chats/setTitle":          true,
+	"/chats/setAbout":          true,
+	"/chats/setRules":          true,
+	"/chats/pinMessage":        true,
+	"/chats/unpinMessage":      true,
+	"/chats/blockUser":         true,
+	"/chats/unblockUser":       true,
+	"/chats/sendActions":       true,
+	"/files/getInfo":           true,
+	downloadPath:               true,
+	"/messages/editText":       true,
+	"/threads/subscribers/get": true,
+	"/threads/autosubscribe":   true,
+}
+
+// IsIdempotentPath reports whether the API method can be safely repeated
+// when it is unknown if the previous attempt has reached the server
+func IsIdempotentPath(path string) bool {
+	return idempotentPaths[path]
+}
+
+// RetryPolicy decides whether a failed request should be repeated.
+type RetryPolicy interface {
+	// NextRetry is called after the failed attempt (starting from 1) to the API method path.
+	// It returns the delay before the next attempt and false if the request should not be repeated.
+	NextRetry(attempt int, path string, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy which doubles (or multiplies by Multiplier)
+// the delay after every failed attempt.
+//
+// Transport errors and 5xx responses are retried only for idempotent methods,
+// throttled requests (429) are retried for all methods, because the server has not processed them.
+type ExponentialBackoff struct {
+	// Max number of attempts including the first one
+	MaxAttempts int
+
+	// Delay before the second attempt
+	InitialInterval time.Duration
+
+	// Upper bound for the delay
+	MaxInterval time.Duration
+
+	// Factor the delay is multiplied by after every attempt
+	Multiplier float64
+
+	// Randomization factor from 0 to 1, the delay is randomly reduced by up to this fraction
+	Jitter float64
+
+	// Do not wait for the Retry-After interval from the API
+	IgnoreRetryAfter bool
+
+	// Idempotent reports whether the API method can be repeated after a transport or server error.
+	// IsIdempotentPath is used if it is nil.
+	Idempotent func(path string) bool
+}
+
+// NewExponentialBackoff returns ExponentialBackoff with default settings:
+// 3 attempts, 500ms initial interval, 10s max interval
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxAttempts:     defaultRetryMaxAttempts,
+		InitialInterval: defaultRetryInitialInterval,
+		MaxInterval:     defaultRetryMaxInterval,
+		Multiplier:      defaultRetryMultiplier,
+		Jitter:          defaultRetryJitter,
+	}
+}
+
+func (b *ExponentialBackoff) NextRetry(attempt int, path string, err error) (time.Duration, bool) {
+	if attempt >= b.MaxAttempts || !b.retryable(path, err) {
+		return 0, false
+	}
+
+	apiErr := &APIError{}
+	if !b.IgnoreRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+
+	return b.delay(attempt), true
+}
+
+func (b *ExponentialBackoff) retryable(path string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+
+	idempotent := IsIdempotentPath
+	if b.Idempotent != nil {
+		idempotent = b.Idempotent
+	}
+
+	return idempotent(path) && (errors.Is(err, ErrTransport) || errors.Is(err, ErrServer))
+}
+
+func (b *ExponentialBackoff) delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if b.MaxInterval > 0 && delay > float64(b.MaxInterval) {
+		delay = float64(b.MaxInterval)
+	}
+
+	if b.Jitter > 0 {
+		delay -= delay * b.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses Retry-After header value: either delay in seconds or HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
+
+// sleepContext waits for the delay or until ctx is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package botgolang
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialBackoff_NextRetry(t *testing.T) {
+	backoff := &ExponentialBackoff{
+		MaxAttempts:     4,
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     300 * time.Millisecond,
+		Multiplier:      2,
+	}
+
+	transportErr := fmt.Errorf("%w: connection reset", ErrTransport)
+	throttled := newAPIError(http.StatusTooManyRequests, "", "/messages/sendText", "")
+	throttled.RetryAfter = 5 * time.Second
+
+	tests := []struct {
+		name      string
+		attempt   int
+		path      string
+		err       error
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{"first", 1, "/chats/getInfo", transportErr, 100 * time.Millisecond, true},
+		{"second", 2, "/chats/getInfo", transportErr, 200 * time.Millisecond, true},
+		{"capped", 3, "/chats/getInfo", transportErr, 300 * time.Millisecond, true},
+		{"max_attempts", 4, "/chats/getInfo", transportErr, 0, false},
+		{"server_error", 1, "/files/getInfo", newAPIError(http.StatusBadGateway, "", "/files/getInfo", ""), 100 * time.Millisecond, true},
+		{"not_idempotent", 1, "/messages/sendText", transportErr, 0, false},
+		{"retry_after", 1, "/messages/sendText", throttled, 5 * time.Second, true},
+		{"api_error", 1, "/chats/getInfo", newAPIError(http.StatusOK, "Chat not found", "/chats/getInfo", ""), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := backoff.NextRetry(tt.attempt, tt.path, tt.err)
+			assert.Equal(t, tt.wantRetry, retry)
+			assert.Equal(t, tt.wantDelay, delay)
+		})
+	}
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		retryPolicy: &ExponentialBackoff{
+			MaxAttempts:     3,
+			InitialInterval: time.Millisecond,
+		},
+	}
+
+	_, err := client.Do("/chats/getInfo", url.Values{}, nil)
+	require.NoError(err)
+	assert.EqualValues(3, atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = client.Do("/messages/sendText", url.Values{}, nil)
+	assert.ErrorIs(err, ErrServer)
+	assert.EqualValues(1, atomic.LoadInt32(&calls))
+}
+
+func TestClient_Do_RetryUpload(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	file, err := os.CreateTemp(t.TempDir(), "upload")
+	require.NoError(err)
+	defer file.Close()
+	_, err = file.Write(data)
+	require.NoError(err)
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(err)
+
+	var calls int32
+	var received []byte
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt is throttled before the body is read
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		part, _, err := r.FormFile("file")
+		if assert.NoError(err) {
+			received, _ = io.ReadAll(part)
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		retryPolicy: &ExponentialBackoff{
+			MaxAttempts:      2,
+			InitialInterval:  time.Millisecond,
+			IgnoreRetryAfter: true,
+		},
+	}
+
+	_, err = client.Do("/messages/sendFile", url.Values{"chatId": {"id_1234"}}, file)
+	require.NoError(err)
+	assert.EqualValues(2, atomic.LoadInt32(&calls))
+	assert.True(bytes.Equal(data, received), "the retried upload differs from the file")
+}
+
+func TestFileUpload_BodyWait(t *testing.T) {
+	reader := &countingReader{Reader: bytes.NewReader(make([]byte, 1<<20))}
+	upload := newFileUpload("file", reader, 0)
+
+	body, _, _, wait, err := upload.body()
+	require.NoError(t, err)
+	_, err = body.Read(make([]byte, 16))
+	require.NoError(t, err)
+
+	wait()
+	reads := atomic.LoadInt32(&reader.reads)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, reads, atomic.LoadInt32(&reader.reads))
+}
+
+type countingReader struct {
+	io.Reader
+	reads int32
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	atomic.AddInt32(&r.reads, 1)
+	return r.Reader.Read(p)
+}
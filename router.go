@@ -0,0 +1,307 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrHandlerPanic is returned by the handler wrapped with RecoverMiddleware if it panics
+	ErrHandlerPanic = errors.New("handler panicked")
+
+	// ErrNotCallbackQuery is returned by HandlerContext.Answer for events other than callbackQuery
+	ErrNotCallbackQuery = errors.New("event is not a callback query")
+)
+
+// HandlerFunc processes the event routed to it
+type HandlerFunc func(c *HandlerContext) error
+
+// HandlerMiddleware wraps the handler, e.g. to log, authorize or recover events
+type HandlerMiddleware func(next HandlerFunc) HandlerFunc
+
+// HandlerContext is passed to the handler with the event and helpers to respond to it
+type HandlerContext struct {
+	bot *Bot
+
+	// The event being handled
+	Event Event
+
+	// Arguments after the command, set by the handlers registered with Router.Command
+	Args []string
+
+	// Submatches of the text, set by the handlers registered with Router.Text
+	Matches []string
+}
+
+// Context returns the context of processing of the event, see Event.Context
+func (c *HandlerContext) Context() context.Context {
+	return c.Event.Context()
+}
+
+// Bot returns the bot which received the event
+func (c *HandlerContext) Bot() *Bot {
+	return c.bot
+}
+
+// Chat returns the chat of the event, for callbackQuery it is the chat of the message with the button
+func (c *HandlerContext) Chat() *Chat {
+	chat := c.Event.chat()
+	chat.client = c.bot.client
+	return &chat
+}
+
+// Message returns the message of the event, for callbackQuery it is the message with the button
+func (c *HandlerContext) Message() *Message {
+	if c.Event.Type == CALLBACK_QUERY {
+		return c.Event.Payload.CallbackMessage()
+	}
+	return c.Event.Payload.Message()
+}
+
+// Send sends the text message to the chat of the event
+func (c *HandlerContext) Send(text string) error {
+	return c.bot.NewTextMessage(c.Event.chat().ID, text).SendWithContext(c.Context())
+}
+
+// Reply replies to the message of the event with the text
+func (c *HandlerContext) Reply(text string) error {
+	return c.Message().ReplyWithContext(c.Context(), text)
+}
+
+// Answer answers the callback query of the event with the text, shown as alert if showAlert is true
+func (c *HandlerContext) Answer(text string, showAlert bool) error {
+	if c.Event.Type != CALLBACK_QUERY {
+		return ErrNotCallbackQuery
+	}
+	return c.bot.NewButtonResponse(c.Event.Payload.QueryID, "", text, showAlert).SendWithContext(c.Context())
+}
+
+type route struct {
+	match   func(c *HandlerContext) bool
+	handler HandlerFunc
+}
+
+// Router dispatches events to handlers registered for them.
+// The first route matching the event handles it, routes are checked in the order of registration.
+// Register routes before Run, the router is not safe for concurrent registration.
+type Router struct {
+	bot         *Bot
+	routes      []route
+	middlewares []HandlerMiddleware
+	fallback    HandlerFunc
+	onError     func(c *HandlerContext, err error)
+	dispatcher  *DispatcherConfig
+}
+
+// NewRouter creates the router of events received by the bot
+func NewRouter(bot *Bot) *Router {
+	r := &Router{bot: bot}
+	r.onError = func(c *HandlerContext, err error) {
+		bot.logger.Error("cannot handle event", LogFields{
+			"eventId": c.Event.EventID,
+			"type":    c.Event.Type,
+			"err":     err,
+		})
+	}
+	return r
+}
+
+// Use adds middlewares applied to all handlers, the first one is the outermost
+func (r *Router) Use(middlewares ...HandlerMiddleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// On registers the handler of all events of the type
+func (r *Router) On(eventType EventType, handler HandlerFunc) {
+	r.add(func(c *HandlerContext) bool {
+		return c.Event.Type == eventType
+	}, handler)
+}
+
+// Command registers the handler of new messages starting with the command, e.g. "/start".
+// The words after the command are passed in HandlerContext.Args.
+func (r *Router) Command(command string, handler HandlerFunc) {
+	command = "/" + strings.TrimPrefix(command, "/")
+	r.add(func(c *HandlerContext) bool {
+		if c.Event.Type != NEW_MESSAGE {
+			return false
+		}
+		fields := strings.Fields(c.Event.Payload.Text)
+		if len(fields) == 0 || fields[0] != command {
+			return false
+		}
+		c.Args = fields[1:]
+		return true
+	}, handler)
+}
+
+// Text registers the handler of new messages with the text matching the expression.
+// The submatches are passed in HandlerContext.Matches.
+func (r *Router) Text(expr *regexp.Regexp, handler HandlerFunc) {
+	r.add(func(c *HandlerContext) bool {
+		if c.Event.Type != NEW_MESSAGE {
+			return false
+		}
+		c.Matches = expr.FindStringSubmatch(c.Event.Payload.Text)
+		return c.Matches != nil
+	}, handler)
+}
+
+// Callback registers the handler of callback queries with the callback data starting with the prefix
+func (r *Router) Callback(prefix string, handler HandlerFunc) {
+	r.add(func(c *HandlerContext) bool {
+		return c.Event.Type == CALLBACK_QUERY && strings.HasPrefix(c.Event.Payload.CallbackData, prefix)
+	}, handler)
+}
+
+// ChatType registers the handler of all events in the chats of the type: private, group or channel
+func (r *Router) ChatType(chatType ChatType, handler HandlerFunc) {
+	r.add(func(c *HandlerContext) bool {
+		return c.Event.chat().Type == chatType
+	}, handler)
+}
+
+// Fallback sets the handler of events not matched by any route, they are skipped by default
+func (r *Router) Fallback(handler HandlerFunc) {
+	r.fallback = handler
+}
+
+// OnError sets the function called with errors returned by handlers, they are logged by default
+func (r *Router) OnError(onError func(c *HandlerContext, err error)) {
+	r.onError = onError
+}
+
+// Concurrent makes Run handle events by the pool of workers, see Dispatcher.
+// Events of one chat are still handled one by one.
+func (r *Router) Concurrent(config DispatcherConfig) {
+	r.dispatcher = &config
+}
+
+func (r *Router) add(match func(c *HandlerContext) bool, handler HandlerFunc) {
+	r.routes = append(r.routes, route{match: match, handler: handler})
+}
+
+// Handle routes the event to its handler and returns the handler error.
+// It ends the event span and acks the event if the handler succeeds.
+func (r *Router) Handle(event Event) error {
+	if event.client == nil {
+		event.bind(r.bot.client)
+	}
+
+	c := &HandlerContext{bot: r.bot, Event: event}
+	handler := r.fallback
+	for _, route := range r.routes {
+		if route.match(c) {
+			handler = route.handler
+			break
+		}
+		c.Args, c.Matches = nil, nil
+	}
+
+	var err error
+	if handler != nil {
+		for i := len(r.middlewares) - 1; i >= 0; i-- {
+			handler = r.middlewares[i](handler)
+		}
+		err = handler(c)
+	}
+
+	event.Done(err)
+	if err != nil {
+		if r.onError != nil {
+			r.onError(c, err)
+		}
+		return err
+	}
+
+	event.Ack()
+	return nil
+}
+
+// Run starts the updater of the bot and handles events one by one, or concurrently
+// if the router is Concurrent, until ctx is done.
+// The events received before ctx is done are handled before Run returns.
+func (r *Router) Run(ctx context.Context) error {
+	events, err := r.bot.Updater().Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.dispatcher != nil {
+		dispatcher := NewDispatcher(*r.dispatcher, func(event Event) {
+			_ = r.Handle(event)
+		})
+		return dispatcher.Run(context.Background(), events)
+	}
+
+	for event := range events {
+		_ = r.Handle(event)
+	}
+
+	return nil
+}
+
+// RecoverMiddleware turns panics of handlers into errors wrapping ErrHandlerPanic
+func RecoverMiddleware() HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *HandlerContext) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = fmt.Errorf("%w: %v\n%s", ErrHandlerPanic, v, debug.Stack())
+				}
+			}()
+			return next(c)
+		}
+	}
+}
+
+// LogMiddleware logs handled events with their duration
+func LogMiddleware(logger Logger) HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *HandlerContext) error {
+			start := time.Now()
+			err := next(c)
+
+			fields := LogFields{
+				"eventId":  c.Event.EventID,
+				"type":     c.Event.Type,
+				"duration": time.Since(start),
+			}
+			if err != nil {
+				fields["err"] = err
+				logger.Warn("event handling failed", fields)
+			} else {
+				logger.Debug("event handled", fields)
+			}
+			return err
+		}
+	}
+}
+
+// AuthMiddleware skips events for which allow returns false, e.g. from unknown users
+func AuthMiddleware(allow func(c *HandlerContext) bool) HandlerMiddleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *HandlerContext) error {
+			if !allow(c) {
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
+
+// AllowUsers returns the function for AuthMiddleware which allows events from the users only
+func AllowUsers(userIDs ...string) func(c *HandlerContext) bool {
+	allowed := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		allowed[id] = true
+	}
+	return func(c *HandlerContext) bool {
+		return allowed[c.Event.Payload.From.ID]
+	}
+}
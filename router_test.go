@@ -0,0 +1,339 @@
+package botgolang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouterTestBot(baseURL string) *Bot {
+	client := &Client{
+		baseURL: baseURL,
+		token:   "test_token",
+		client:  &http.Client{},
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+	}
+	logger := NewLogrusLogger(&logrus.Logger{})
+	return &Bot{
+		client:  client,
+		updater: newUpdater(client, 0, logger),
+		logger:  logger,
+	}
+}
+
+func newMessageEvent(text string, chatType ChatType) Event {
+	event := Event{Type: NEW_MESSAGE}
+	event.Payload.MsgID = "100"
+	event.Payload.Text = text
+	event.Payload.Chat = Chat{ID: "chat", Type: chatType}
+	event.Payload.From.ID = "user"
+	return event
+}
+
+func TestRouter_Routes(t *testing.T) {
+	assert := assert.New(t)
+
+	router := NewRouter(newRouterTestBot(""))
+
+	var handled string
+	var args, matches []string
+	router.Command("/start", func(c *HandlerContext) error {
+		handled, args = "start", c.Args
+		return nil
+	})
+	router.Text(regexp.MustCompile(`^order (\d+)$`), func(c *HandlerContext) error {
+		handled, matches = "order", c.Matches
+		return nil
+	})
+	router.Callback("vote:", func(c *HandlerContext) error {
+		handled = "vote"
+		return nil
+	})
+	router.ChatType(Group, func(c *HandlerContext) error {
+		handled = "group"
+		return nil
+	})
+	router.On(DELETED_MESSAGE, func(c *HandlerContext) error {
+		handled = "deleted"
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		event   Event
+		handled string
+	}{
+		{"command", newMessageEvent("/start now please", Private), "start"},
+		{"command in group", newMessageEvent("/start", Group), "start"},
+		{"not a command", newMessageEvent("/started", Private), ""},
+		{"text", newMessageEvent("order 42", Private), "order"},
+		{"chat type", newMessageEvent("hello", Group), "group"},
+		{"callback", Event{Type: CALLBACK_QUERY, Payload: EventPayload{CallbackData: "vote:yes"}}, "vote"},
+		{"other callback", Event{Type: CALLBACK_QUERY, Payload: EventPayload{CallbackData: "echo"}}, ""},
+		{"event type", Event{Type: DELETED_MESSAGE}, "deleted"},
+	}
+
+	for _, test := range tests {
+		handled = ""
+		assert.NoError(router.Handle(test.event), test.name)
+		assert.Equal(test.handled, handled, test.name)
+	}
+
+	router.Handle(newMessageEvent("/start now please", Private))
+	assert.Equal([]string{"now", "please"}, args)
+	router.Handle(newMessageEvent("order 42", Private))
+	assert.Equal([]string{"order 42", "42"}, matches)
+
+	var fallback EventType
+	router.Fallback(func(c *HandlerContext) error {
+		fallback = c.Event.Type
+		return nil
+	})
+	assert.NoError(router.Handle(Event{Type: PINNED_MESSAGE}))
+	assert.Equal(PINNED_MESSAGE, fallback)
+}
+
+func TestRouter_Middleware(t *testing.T) {
+	assert := assert.New(t)
+
+	router := NewRouter(newRouterTestBot(""))
+
+	var calls []string
+	trace := func(name string) HandlerMiddleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *HandlerContext) error {
+				calls = append(calls, name)
+				return next(c)
+			}
+		}
+	}
+
+	var handlerErr error
+	router.OnError(func(c *HandlerContext, err error) {
+		handlerErr = err
+	})
+	router.Use(trace("first"), RecoverMiddleware(), trace("second"))
+	router.Use(AuthMiddleware(AllowUsers("user")))
+	router.On(NEW_MESSAGE, func(c *HandlerContext) error {
+		calls = append(calls, "handler")
+		panic("boom")
+	})
+
+	err := router.Handle(newMessageEvent("hello", Private))
+	assert.ErrorIs(err, ErrHandlerPanic)
+	assert.ErrorIs(handlerErr, ErrHandlerPanic)
+	assert.Equal([]string{"first", "second", "handler"}, calls)
+
+	calls = nil
+	event := newMessageEvent("hello", Private)
+	event.Payload.From.ID = "stranger"
+	assert.NoError(router.Handle(event))
+	assert.Equal([]string{"first", "second"}, calls)
+}
+
+func TestRouter_Ack(t *testing.T) {
+	assert := assert.New(t)
+
+	router := NewRouter(newRouterTestBot(""))
+	router.OnError(nil)
+
+	errHandler := errors.New("handler error")
+	router.On(NEW_MESSAGE, func(c *HandlerContext) error {
+		if c.Event.Payload.Text == "fail" {
+			return errHandler
+		}
+		return nil
+	})
+
+	var acked []string
+	handle := func(text string) error {
+		event := newMessageEvent(text, Private)
+		event.ack = func() { acked = append(acked, text) }
+		return router.Handle(event)
+	}
+
+	assert.NoError(handle("ok"))
+	assert.ErrorIs(handle("fail"), errHandler)
+	assert.Equal([]string{"ok"}, acked)
+}
+
+func TestHandlerContext_Helpers(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var mu sync.Mutex
+	requests := map[string]url.Values{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(r.ParseForm())
+		mu.Lock()
+		requests[r.URL.Path] = r.Form
+		mu.Unlock()
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	router := NewRouter(newRouterTestBot(testServer.URL))
+	router.On(NEW_MESSAGE, func(c *HandlerContext) error {
+		assert.Equal("chat", c.Chat().ID)
+		assert.ErrorIs(c.Answer("no", false), ErrNotCallbackQuery)
+		return c.Reply("pong")
+	})
+	router.On(CALLBACK_QUERY, func(c *HandlerContext) error {
+		return c.Answer("done", true)
+	})
+
+	require.NoError(router.Handle(newMessageEvent("ping", Private)))
+	assert.Equal("pong", requests["/messages/sendText"].Get("text"))
+	assert.Equal("100", requests["/messages/sendText"].Get("replyMsgId"))
+	assert.Equal("chat", requests["/messages/sendText"].Get("chatId"))
+
+	require.NoError(router.Handle(Event{Type: CALLBACK_QUERY, Payload: EventPayload{QueryID: "SVR:1"}}))
+	assert.Equal("SVR:1", requests["/messages/answerCallbackQuery"].Get("queryId"))
+	assert.Equal("done", requests["/messages/answerCallbackQuery"].Get("text"))
+}
+
+func TestRouter_CallbackChat(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var mu sync.Mutex
+	requests := map[string]url.Values{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(r.ParseForm())
+		mu.Lock()
+		requests[r.URL.Path] = r.Form
+		mu.Unlock()
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	// the callback query of the mock events has the chat only in the message with the button
+	callback := *mockEvents(t)[7]
+	require.Equal(CALLBACK_QUERY, callback.Type)
+
+	router := NewRouter(newRouterTestBot(testServer.URL))
+	var chat *Chat
+	router.ChatType(Private, func(c *HandlerContext) error {
+		chat = c.Chat()
+		return c.Send("pressed")
+	})
+
+	require.NoError(router.Handle(callback))
+	require.NotNil(chat)
+	assert.Equal("1234567890", chat.ID)
+	assert.Equal(Private, chat.Type)
+	assert.Equal("1234567890", requests["/messages/sendText"].Get("chatId"))
+	assert.Equal("pressed", requests["/messages/sendText"].Get("text"))
+}
+
+func TestRouter_Run(t *testing.T) {
+	assert := assert.New(t)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events/get" && r.FormValue("lastEventId") != "0" {
+			<-r.Context().Done()
+			return
+		}
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	router := NewRouter(newRouterTestBot(testServer.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var handled []int
+	router.Fallback(func(c *HandlerContext) error {
+		handled = append(handled, c.Event.EventID)
+		if len(handled) == 8 {
+			cancel()
+		}
+		return nil
+	})
+
+	done := make(chan error)
+	go func() { done <- router.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(err)
+	case <-time.After(time.Second):
+		t.Fatal("router has not stopped")
+	}
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8}, handled)
+}
+
+func TestRouter_RunDrain(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		t.Run(fmt.Sprintf("concurrent_%v", concurrent), func(t *testing.T) {
+			var polls int32
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/events/get" && atomic.AddInt32(&polls, 1) > 1 {
+					time.Sleep(10 * time.Millisecond)
+					_, _ = w.Write([]byte(`{"ok":true,"events":[]}`))
+					return
+				}
+				(&MockHandler{}).ServeHTTP(w, r)
+			}))
+			defer func() { testServer.Close() }()
+
+			bot := newRouterTestBot(testServer.URL)
+			bot.updater.bufferSize = 8
+			router := NewRouter(bot)
+			if concurrent {
+				router.Concurrent(DispatcherConfig{Workers: 1})
+			}
+
+			started, release := make(chan struct{}), make(chan struct{})
+			var mu sync.Mutex
+			replies := map[int]error{}
+			router.Fallback(func(c *HandlerContext) error {
+				if c.Event.EventID == 1 {
+					close(started)
+					<-release
+				}
+				var err error
+				if c.Event.Type == CALLBACK_QUERY {
+					err = c.Answer("done", false)
+				} else {
+					err = c.Send("pong")
+				}
+				mu.Lock()
+				replies[c.Event.EventID] = err
+				mu.Unlock()
+				return nil
+			})
+
+			done := make(chan error)
+			go func() { done <- router.Run(context.Background()) }()
+
+			<-started
+			require.NoError(t, bot.Updater().Stop(context.Background()))
+			close(release)
+
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("router has not stopped")
+			}
+
+			// the events drained after Stop are answered
+			assert.Len(t, replies, 8)
+			for eventID, err := range replies {
+				assert.NoError(t, err, "event %d", eventID)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package botgolang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultTokenCheckInterval = 5 * time.Second
+
+// ErrEmptyToken is returned by token providers when there is no token
+var ErrEmptyToken = errors.New("token is empty")
+
+// TokenProvider returns the token of the bot. It is called before every request,
+// so a new token takes effect with the next request, while requests in flight finish with the old one.
+type TokenProvider interface {
+	Token() (string, error)
+}
+
+// StaticToken is the token which never changes
+type StaticToken string
+
+func (t StaticToken) Token() (string, error) {
+	if t == "" {
+		return "", ErrEmptyToken
+	}
+	return string(t), nil
+}
+
+// EnvToken reads the token from the environment variable with the given name on every request
+type EnvToken string
+
+func (t EnvToken) Token() (string, error) {
+	token := os.Getenv(string(t))
+	if token == "" {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrEmptyToken, string(t))
+	}
+	return token, nil
+}
+
+// FileToken reads the token from the file and rereads it when the file changes,
+// e.g. a mounted Kubernetes secret. Leading and trailing spaces are trimmed.
+type FileToken struct {
+	path string
+
+	// how often the file is checked for changes
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	token     string
+	stamps    []fileStamp
+	checkedAt time.Time
+}
+
+// NewFileToken reads the token from the file.
+// The file is checked for changes not more often than once in checkInterval, 5 seconds if zero.
+func NewFileToken(path string, checkInterval time.Duration) (*FileToken, error) {
+	if checkInterval <= 0 {
+		checkInterval = defaultTokenCheckInterval
+	}
+
+	t := &FileToken{path: path, checkInterval: checkInterval}
+	if _, err := t.Token(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *FileToken) Token() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.token != "" && now.Sub(t.checkedAt) < t.checkInterval {
+		return t.token, nil
+	}
+	t.checkedAt = now
+
+	stamps, err := statFiles(t.path)
+	if err != nil {
+		return t.fallback(fmt.Errorf("cannot read token: %w", err))
+	}
+	if t.token != "" && sameStamps(stamps, t.stamps) {
+		return t.token, nil
+	}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return t.fallback(fmt.Errorf("cannot read token: %w", err))
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return t.fallback(fmt.Errorf("%w: file %s is empty", ErrEmptyToken, t.path))
+	}
+
+	t.token, t.stamps = token, stamps
+	return token, nil
+}
+
+// fallback keeps the previous token while the file is being rewritten
+func (t *FileToken) fallback(err error) (string, error) {
+	if t.token != "" {
+		return t.token, nil
+	}
+	return "", err
+}
+
+// currentToken returns the token for the next request
+func (c *Client) currentToken() (string, error) {
+	if c.tokenProvider == nil {
+		return c.token, nil
+	}
+
+	token, err := c.tokenProvider.Token()
+	if err != nil {
+		return "", fmt.Errorf("cannot get token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,96 @@
+package botgolang
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileToken(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "token")
+	_, err := NewFileToken(path, time.Nanosecond)
+	assert.Error(err)
+
+	require.NoError(os.WriteFile(path, []byte("token_1\n"), 0o600))
+	provider, err := NewFileToken(path, time.Nanosecond)
+	require.NoError(err)
+
+	token, err := provider.Token()
+	require.NoError(err)
+	assert.Equal("token_1", token)
+
+	require.NoError(os.WriteFile(path, []byte("token_22"), 0o600))
+	token, err = provider.Token()
+	require.NoError(err)
+	assert.Equal("token_22", token)
+
+	// the previous token is kept while the file is missing or empty
+	require.NoError(os.WriteFile(path, nil, 0o600))
+	token, err = provider.Token()
+	require.NoError(err)
+	assert.Equal("token_22", token)
+}
+
+func TestEnvToken(t *testing.T) {
+	t.Setenv("TEST_BOT_TOKEN", "env_token")
+
+	token, err := EnvToken("TEST_BOT_TOKEN").Token()
+	require.NoError(t, err)
+	assert.Equal(t, "env_token", token)
+
+	_, err = EnvToken("TEST_BOT_TOKEN_MISSING").Token()
+	assert.ErrorIs(t, err, ErrEmptyToken)
+
+	_, err = StaticToken("").Token()
+	assert.ErrorIs(t, err, ErrEmptyToken)
+}
+
+type testTokenProvider struct {
+	token string
+}
+
+func (p *testTokenProvider) Token() (string, error) {
+	return p.token, nil
+}
+
+func TestUpdater_TokenRotation(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var tokens, lastEventIDs []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.FormValue("token"))
+		lastEventIDs = append(lastEventIDs, r.FormValue("lastEventId"))
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	provider := &testTokenProvider{token: "token_1"}
+	client := &Client{
+		baseURL:       testServer.URL,
+		client:        http.DefaultClient,
+		logger:        NewLogrusLogger(&logrus.Logger{}),
+		tokenProvider: provider,
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+
+	_, err := updater.GetLastEvents(0)
+	require.NoError(err)
+
+	provider.token = "token_2"
+	_, err = updater.GetLastEvents(0)
+	require.NoError(err)
+
+	assert.Equal([]string{"token_1", "token_2"}, tokens)
+	assert.Equal([]string{"0", "8"}, lastEventIDs)
+}
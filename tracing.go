@@ -0,0 +1,105 @@
+package botgolang
+
+import (
+	"context"
+	"strconv"
+)
+
+const requestIDParam = "request-id"
+
+// SpanAttributes are key-value pairs describing the span
+type SpanAttributes map[string]interface{}
+
+// Tracer starts spans around API calls and processing of events.
+// Implement it to connect the bot to OpenTelemetry or any other tracing system.
+type Tracer interface {
+	// Start opens a span which is a child of the span in ctx, if there is one.
+	// The returned context carries the new span.
+	Start(ctx context.Context, name string, attrs SpanAttributes) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	SetAttributes(attrs SpanAttributes)
+
+	// End finishes the span, err is the result of the operation or nil
+	End(err error)
+}
+
+type noopTracer struct{}
+
+// NoopTracer returns the Tracer which does nothing, it is used by default
+func NoopTracer() Tracer {
+	return noopTracer{}
+}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs SpanAttributes) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs SpanAttributes) {}
+
+func (noopSpan) End(err error) {}
+
+// requestIDPaths are API methods accepting request-id param
+var requestIDPaths = map[string]bool{
+	"/messages/sendText":             true,
+	"/messages/sendTextWithDeeplink": true,
+	"/messages/sendFile":             true,
+	"/messages/sendVoice":            true,
+	"/messages/editText":             true,
+	"/messages/deleteMessages":       true,
+	"/messages/answerCallbackQuery":  true,
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns the context carrying requestID.
+// It is sent as request-id to all API methods accepting it, unless the request sets its own one.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id carried by ctx or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// eventRequestID is the request id of calls made while processing the event
+func eventRequestID(eventID int) string {
+	return "event-" + strconv.Itoa(eventID)
+}
+
+// traceCall sets the request id of the call and wraps it into a span
+func (c *Client) traceCall(next CallHandler) CallHandler {
+	return func(ctx context.Context, call *Call) (*CallResult, error) {
+		if requestID := RequestIDFromContext(ctx); requestID != "" && requestIDPaths[call.Path] && call.Params.Get(requestIDParam) == "" {
+			call.Params.Set(requestIDParam, requestID)
+		}
+
+		attrs := SpanAttributes{"path": call.Path}
+		if requestID := call.Params.Get(requestIDParam); requestID != "" {
+			attrs["request_id"] = requestID
+		}
+		if chatID := call.Params.Get("chatId"); chatID != "" {
+			attrs["chat_id"] = c.redact.redact(c.redact.UserIDs, chatID)
+		}
+
+		tracer := c.tracer
+		if tracer == nil {
+			tracer = NoopTracer()
+		}
+
+		ctx, span := tracer.Start(ctx, call.Path, attrs)
+		result, err := next(ctx, call)
+		if result != nil {
+			span.SetAttributes(SpanAttributes{"ok": result.Response.OK})
+		}
+		span.End(err)
+
+		return result, err
+	}
+}
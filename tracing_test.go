@@ -0,0 +1,127 @@
+package botgolang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  SpanAttributes
+	ended  bool
+	err    error
+}
+
+func (s *testSpan) SetAttributes(attrs SpanAttributes) {
+	for key, value := range attrs {
+		s.attrs[key] = value
+	}
+}
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type testSpanKey struct{}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs SpanAttributes) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attrs: attrs}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestClient_Tracing(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	requestIDs := map[string]string{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs[r.URL.Path] = r.URL.Query().Get("request-id")
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { testServer.Close() }()
+
+	tracer := &testTracer{}
+	client := &Client{
+		baseURL: testServer.URL,
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		tracer:  tracer,
+	}
+	updater := newUpdater(client, 0, NewLogrusLogger(&logrus.Logger{}))
+
+	event := &Event{EventID: 42, Type: NEW_MESSAGE, Payload: EventPayload{client: client}}
+	event.Payload.Chat.ID = "id_1234"
+	updater.traceEvent(context.Background(), event)
+	assert.Equal("event-42", RequestIDFromContext(event.Context()))
+
+	ctx := event.Context()
+	require.NoError((&Message{client: client, ID: "1", Chat: Chat{ID: "id_1234"}, Text: "text"}).EditWithContext(ctx))
+	require.NoError((&Message{client: client, Chat: Chat{ID: "id_1234"}, Text: "text", RequestID: "own"}).SendWithContext(ctx))
+	_, err := client.GetChatInfoWithContext(ctx, "id_1234")
+	require.NoError(err)
+	event.Done(nil)
+
+	assert.Equal("event-42", requestIDs["/messages/editText"])
+	assert.Equal("own", requestIDs["/messages/sendText"])
+	assert.Equal("", requestIDs["/chats/getInfo"])
+
+	require.Len(tracer.spans, 4)
+	root := tracer.spans[0]
+	assert.Equal("event newMessage", root.name)
+	assert.Equal(42, root.attrs["event_id"])
+	assert.True(root.ended)
+
+	for i, path := range []string{"/messages/editText", "/messages/sendText", "/chats/getInfo"} {
+		span := tracer.spans[i+1]
+		assert.Equal(path, span.name)
+		assert.Same(root, span.parent)
+		assert.Equal(true, span.attrs["ok"])
+		assert.True(span.ended)
+		assert.NoError(span.err)
+	}
+	assert.Equal("own", tracer.spans[2].attrs["request_id"])
+	assert.Equal("id_1234", root.attrs["chat_id"])
+
+	callback := &Event{EventID: 43, Type: CALLBACK_QUERY}
+	callback.Payload.CallbackMsg.Chat.ID = "id_5678"
+	updater.traceEvent(context.Background(), callback)
+	assert.Equal("id_5678", tracer.spans[4].attrs["chat_id"])
+}
+
+func TestClient_Tracing_Error(t *testing.T) {
+	tracer := &testTracer{}
+	client := &Client{
+		baseURL: "http://127.0.0.1:0",
+		token:   "test_token",
+		client:  http.DefaultClient,
+		logger:  NewLogrusLogger(&logrus.Logger{}),
+		tracer:  tracer,
+	}
+
+	_, err := client.GetChatInfoWithContext(ContextWithRequestID(context.Background(), "req_1"), "id_1234")
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	assert.ErrorIs(t, tracer.spans[0].err, ErrTransport)
+	assert.NotContains(t, tracer.spans[0].attrs, "request_id")
+}
@@ -0,0 +1,222 @@
+package botgolang
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestNewTransport_TLS(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var commonName string
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	testServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	testServer.StartTLS()
+	defer func() { testServer.Close() }()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: testServer.Certificate().Raw,
+	}), 0o600))
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	start := time.Now().Add(-time.Minute)
+	writeCertificate(t, certFile, keyFile, "client_1", start)
+
+	transport, err := NewTransport(TransportConfig{
+		RootCAFiles:         []string{caFile},
+		ClientCertificates:  []CertificateFiles{{CertFile: certFile, KeyFile: keyFile}},
+		MinTLSVersion:       tls.VersionTLS13,
+		MaxIdleConnsPerHost: 4,
+	})
+	require.NoError(err)
+	assert.Equal(4, transport.MaxIdleConnsPerHost)
+
+	// every request makes a new handshake
+	transport.DisableKeepAlives = true
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(testServer.URL + "/messages/sendText?token=test_token")
+	require.NoError(err)
+	resp.Body.Close()
+	assert.Equal("client_1", commonName)
+	assert.Equal(uint16(tls.VersionTLS13), resp.TLS.Version)
+
+	writeCertificate(t, certFile, keyFile, "client_2", start.Add(time.Second))
+
+	resp, err = client.Get(testServer.URL + "/messages/sendText?token=test_token")
+	require.NoError(err)
+	resp.Body.Close()
+	assert.Equal("client_2", commonName)
+
+	untrusted, err := NewTransport(TransportConfig{
+		ClientCertificates: []CertificateFiles{{CertFile: certFile, KeyFile: keyFile}},
+	})
+	require.NoError(err)
+	_, err = (&http.Client{Transport: untrusted}).Get(testServer.URL)
+	assert.Error(err)
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	var proxyAuth, requestURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		requestURL = r.URL.String()
+		(&MockHandler{}).ServeHTTP(w, r)
+	}))
+	defer func() { proxy.Close() }()
+
+	transport, err := NewTransport(TransportConfig{
+		ProxyURL:      proxy.URL,
+		ProxyUsername: "user",
+		ProxyPassword: "secret",
+	})
+	require.NoError(err)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://api.example.com/messages/sendText?token=test_token")
+	require.NoError(err)
+	resp.Body.Close()
+
+	assert.Equal("Basic dXNlcjpzZWNyZXQ=", proxyAuth)
+	assert.Equal("http://api.example.com/messages/sendText?token=test_token", requestURL)
+}
+
+func TestNewTransport_InvalidFiles(t *testing.T) {
+	_, err := NewTransport(TransportConfig{RootCAFiles: []string{filepath.Join(t.TempDir(), "missing.pem")}})
+	assert.Error(t, err)
+
+	_, err = NewBot("test_token", BotTransport(TransportConfig{ProxyURL: "://proxy"}))
+	assert.Error(t, err)
+}
+
+// newHostServer starts the TLS server with the self-signed certificate valid only for the given hosts
+// and returns it with the PEM file of the certificate
+func newHostServer(t *testing.T, dnsNames []string, ips []net.IP) (*httptest.Server, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "test server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              dnsNames,
+		IPAddresses:           ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	testServer := httptest.NewUnstartedServer(&MockHandler{})
+	testServer.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	testServer.StartTLS()
+	t.Cleanup(testServer.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return testServer, caFile
+}
+
+func TestNewTransport_RootCAHostName(t *testing.T) {
+	tests := []struct {
+		name     string
+		dnsNames []string
+		ips      []net.IP
+		host     string
+		wantErr  bool
+	}{
+		{"ip_host", nil, []net.IP{net.IPv4(127, 0, 0, 1)}, "127.0.0.1", false},
+		{"dns_host", []string{"localhost"}, nil, "localhost", false},
+		{"wrong_ip_host", []string{"evil.example.com"}, nil, "127.0.0.1", true},
+		{"wrong_dns_host", []string{"evil.example.com"}, nil, "localhost", true},
+		{"other_ip_host", nil, []net.IP{net.IPv4(10, 0, 0, 1)}, "127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer, caFile := newHostServer(t, tt.dnsNames, tt.ips)
+			_, port, err := net.SplitHostPort(testServer.Listener.Addr().String())
+			require.NoError(t, err)
+
+			transport, err := NewTransport(TransportConfig{RootCAFiles: []string{caFile}})
+			require.NoError(t, err)
+
+			resp, err := (&http.Client{Transport: transport}).Get("https://" + net.JoinHostPort(tt.host, port) + "/self/get?token=test_token")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestRootCAReloader_Verify(t *testing.T) {
+	testServer, caFile := newHostServer(t, []string{"evil.example.com"}, nil)
+	roots := &rootCAReloader{files: []string{caFile}}
+
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{testServer.Certificate()}}
+	assert.Error(t, roots.verify(state))
+
+	state.ServerName = "api.example.com"
+	assert.Error(t, roots.verify(state))
+
+	state.ServerName = "evil.example.com"
+	assert.NoError(t, roots.verify(state))
+}
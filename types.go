@@ -1,5 +1,7 @@
 package botgolang
 
+import "context"
+
 //go:generate easyjson -all types.go
 
 type EventType string
@@ -124,8 +126,12 @@ type BaseEventPayload struct {
 	ParentMessage *ParentMessage `json:"parent_topic"`
 }
 
+// EventPayload keeps its raw JSON, it is decoded by eventPayloadJSON
+//
+//easyjson:skip
 type EventPayload struct {
 	client *Client
+	raw    []byte
 	BaseEventPayload
 
 	// Parts of the message.
@@ -151,6 +157,59 @@ type EventPayload struct {
 	AddedBy Contact `json:"addedBy"`
 
 	RemovedBy Contact `json:"removedBy"`
+
+	// Timestamp of the edit.
+	// Presented only in editedMessage event.
+	EditedTimestamp int `json:"editedTimestamp"`
+}
+
+// Context returns the context of processing of the event.
+// It carries the root span of the event and the request id sent with API calls made using it.
+// It is not canceled when the updater stops, so events received before Stop can be processed.
+func (e *Event) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// Done ends the root span of the event, err is the result of processing or nil.
+// It must be called once, when the event is processed.
+func (e *Event) Done(err error) {
+	if e.span != nil {
+		e.span.End(err)
+	}
+}
+
+// Ack marks the event processed in at-least-once delivery mode, see BotAckTimeout.
+// The offset is committed when all events delivered before it are acked as well.
+// Ack of a redelivered event acks all its copies. It does nothing in the default mode.
+func (e *Event) Ack() {
+	if e.ack != nil {
+		e.ack()
+	}
+}
+
+// DeliveryAttempt returns 1 for the first delivery of the event and more for redeliveries
+// of unacked events. Together with EventID it lets handlers skip already processed events.
+func (e *Event) DeliveryAttempt() int {
+	if e.attempt == 0 {
+		return 1
+	}
+	return e.attempt
+}
+
+// Redelivered reports whether the event was delivered before and not acked in time
+func (e *Event) Redelivered() bool {
+	return e.attempt > 1
+}
+
+// chat returns the chat of the event, callbackQuery events have it only in the message with the button
+func (e *Event) chat() Chat {
+	if e.Type == CALLBACK_QUERY {
+		return e.Payload.CallbackMsg.Chat
+	}
+	return e.Payload.Chat
 }
 
 func (ep *EventPayload) Message() *Message {
@@ -178,6 +237,12 @@ type PartMessage struct {
 	MsgID     string  `json:"msgId"`
 	Text      string  `json:"text"`
 	Timestamp int     `json:"timestamp"`
+
+	// Original chat of the forwarded message
+	Chat Chat `json:"chat"`
+
+	// Parts of the quoted message
+	Parts []Part `json:"parts"`
 }
 
 type PartPayload struct {
@@ -188,11 +253,25 @@ type PartPayload struct {
 	Caption     string      `json:"caption"`
 	Type        string      `json:"type"`
 	PartMessage PartMessage `json:"message"`
-	Message     PartMessage `json:"-"`
+
+	// Message is the same as PartMessage
+	Message PartMessage `json:"-"`
 }
 
+// Event keeps its raw JSON, it is decoded by eventJSON
+//
+//easyjson:skip
 type Event struct {
 	client *Client
+	raw    []byte
+
+	// context and root span of processing of the event
+	ctx  context.Context
+	span Span
+
+	// acknowledgement of the event and the delivery attempt in at-least-once delivery mode
+	ack     func()
+	attempt int
 
 	// Id of the event
 	EventID int `json:"eventId"`
@@ -204,7 +283,13 @@ type Event struct {
 	Payload EventPayload `json:"payload"`
 }
 
+// Part keeps its raw JSON, it is decoded by partJSON
+//
+//easyjson:skip
 type Part struct {
+	client *Client
+	raw    []byte
+
 	// Type of the part
 	Type PartType `json:"type"`
 
@@ -212,6 +297,17 @@ type Part struct {
 	Payload PartPayload `json:"payload"`
 }
 
+// Types with the fields of Event, EventPayload and Part for the generated decoders
+
+//easyjson:json
+type eventJSON Event
+
+//easyjson:json
+type eventPayloadJSON EventPayload
+
+//easyjson:json
+type partJSON Part
+
 func (ep *EventPayload) CallbackQuery() *ButtonResponse {
 	return &ButtonResponse{
 		client:       ep.client,
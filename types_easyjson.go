@@ -17,7 +17,80 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang(in *jlexer.Lexer, out *eventsResponse) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang(in *jlexer.Lexer, out *partJSON) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = PartType(in.String())
+		case "payload":
+			(out.Payload).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang(out *jwriter.Writer, in partJSON) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"payload\":"
+		out.RawString(prefix)
+		(in.Payload).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v partJSON) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComMailRuImBotGolang(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v partJSON) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComMailRuImBotGolang(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *partJSON) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComMailRuImBotGolang(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *partJSON) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComMailRuImBotGolang(l, v)
+}
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(in *jlexer.Lexer, out *eventsResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -79,7 +152,7 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang(in *jlexer.Lexer, out *eve
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang(out *jwriter.Writer, in eventsResponse) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(out *jwriter.Writer, in eventsResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -114,27 +187,27 @@ func easyjson6601e8cdEncodeGithubComMailRuImBotGolang(out *jwriter.Writer, in ev
 // MarshalJSON supports json.Marshaler interface
 func (v eventsResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6601e8cdEncodeGithubComMailRuImBotGolang(&w, v)
+	easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v eventsResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6601e8cdEncodeGithubComMailRuImBotGolang(w, v)
+	easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *eventsResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6601e8cdDecodeGithubComMailRuImBotGolang(&r, v)
+	easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *eventsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6601e8cdDecodeGithubComMailRuImBotGolang(l, v)
+	easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(in *jlexer.Lexer, out *UsersListResponse) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang2(in *jlexer.Lexer, out *eventPayloadJSON) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -153,29 +226,107 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(in *jlexer.Lexer, out *Us
 			continue
 		}
 		switch key {
-		case "users":
+		case "parts":
 			if in.IsNull() {
 				in.Skip()
-				out.List = nil
+				out.Parts = nil
 			} else {
 				in.Delim('[')
-				if out.List == nil {
+				if out.Parts == nil {
 					if !in.IsDelim(']') {
-						out.List = make([]User, 0, 4)
+						out.Parts = make([]Part, 0, 0)
 					} else {
-						out.List = []User{}
+						out.Parts = []Part{}
 					}
 				} else {
-					out.List = (out.List)[:0]
+					out.Parts = (out.Parts)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 User
+					var v4 Part
 					(v4).UnmarshalEasyJSON(in)
-					out.List = append(out.List, v4)
+					out.Parts = append(out.Parts, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "queryId":
+			out.QueryID = string(in.String())
+		case "message":
+			(out.CallbackMsg).UnmarshalEasyJSON(in)
+		case "callbackData":
+			out.CallbackData = string(in.String())
+		case "leftMembers":
+			if in.IsNull() {
+				in.Skip()
+				out.LeftMembers = nil
+			} else {
+				in.Delim('[')
+				if out.LeftMembers == nil {
+					if !in.IsDelim(']') {
+						out.LeftMembers = make([]Contact, 0, 1)
+					} else {
+						out.LeftMembers = []Contact{}
+					}
+				} else {
+					out.LeftMembers = (out.LeftMembers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 Contact
+					(v5).UnmarshalEasyJSON(in)
+					out.LeftMembers = append(out.LeftMembers, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "newMembers":
+			if in.IsNull() {
+				in.Skip()
+				out.NewMembers = nil
+			} else {
+				in.Delim('[')
+				if out.NewMembers == nil {
+					if !in.IsDelim(']') {
+						out.NewMembers = make([]Contact, 0, 1)
+					} else {
+						out.NewMembers = []Contact{}
+					}
+				} else {
+					out.NewMembers = (out.NewMembers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 Contact
+					(v6).UnmarshalEasyJSON(in)
+					out.NewMembers = append(out.NewMembers, v6)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "addedBy":
+			(out.AddedBy).UnmarshalEasyJSON(in)
+		case "removedBy":
+			(out.RemovedBy).UnmarshalEasyJSON(in)
+		case "editedTimestamp":
+			out.EditedTimestamp = int(in.Int())
+		case "msgId":
+			out.MsgID = string(in.String())
+		case "chat":
+			(out.Chat).UnmarshalEasyJSON(in)
+		case "from":
+			(out.From).UnmarshalEasyJSON(in)
+		case "text":
+			out.Text = string(in.String())
+		case "timestamp":
+			out.Timestamp = int(in.Int())
+		case "parent_topic":
+			if in.IsNull() {
+				in.Skip()
+				out.ParentMessage = nil
+			} else {
+				if out.ParentMessage == nil {
+					out.ParentMessage = new(ParentMessage)
+				}
+				(*out.ParentMessage).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -186,119 +337,149 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(in *jlexer.Lexer, out *Us
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(out *jwriter.Writer, in UsersListResponse) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang2(out *jwriter.Writer, in eventPayloadJSON) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"users\":"
+		const prefix string = ",\"parts\":"
 		out.RawString(prefix[1:])
-		if in.List == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.Parts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v5, v6 := range in.List {
-				if v5 > 0 {
+			for v7, v8 := range in.Parts {
+				if v7 > 0 {
 					out.RawByte(',')
 				}
-				(v6).MarshalEasyJSON(out)
+				(v8).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
 	}
-	out.RawByte('}')
-}
-
-// MarshalJSON supports json.Marshaler interface
-func (v UsersListResponse) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UsersListResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6601e8cdEncodeGithubComMailRuImBotGolang1(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *UsersListResponse) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UsersListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6601e8cdDecodeGithubComMailRuImBotGolang1(l, v)
-}
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang2(in *jlexer.Lexer, out *UserState) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
+	{
+		const prefix string = ",\"queryId\":"
+		out.RawString(prefix)
+		out.String(string(in.QueryID))
 	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		(in.CallbackMsg).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"callbackData\":"
+		out.RawString(prefix)
+		out.String(string(in.CallbackData))
+	}
+	{
+		const prefix string = ",\"leftMembers\":"
+		out.RawString(prefix)
+		if in.LeftMembers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v9, v10 := range in.LeftMembers {
+				if v9 > 0 {
+					out.RawByte(',')
+				}
+				(v10).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
 		}
-		switch key {
-		case "lastseen":
-			out.Lastseen = int(in.Int())
-		default:
-			in.SkipRecursive()
+	}
+	{
+		const prefix string = ",\"newMembers\":"
+		out.RawString(prefix)
+		if in.NewMembers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.NewMembers {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
 		}
-		in.WantComma()
 	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+	{
+		const prefix string = ",\"addedBy\":"
+		out.RawString(prefix)
+		(in.AddedBy).MarshalEasyJSON(out)
 	}
-}
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang2(out *jwriter.Writer, in UserState) {
-	out.RawByte('{')
-	first := true
-	_ = first
 	{
-		const prefix string = ",\"lastseen\":"
-		out.RawString(prefix[1:])
-		out.Int(int(in.Lastseen))
+		const prefix string = ",\"removedBy\":"
+		out.RawString(prefix)
+		(in.RemovedBy).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"editedTimestamp\":"
+		out.RawString(prefix)
+		out.Int(int(in.EditedTimestamp))
+	}
+	{
+		const prefix string = ",\"msgId\":"
+		out.RawString(prefix)
+		out.String(string(in.MsgID))
+	}
+	{
+		const prefix string = ",\"chat\":"
+		out.RawString(prefix)
+		(in.Chat).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		(in.From).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Int(int(in.Timestamp))
+	}
+	{
+		const prefix string = ",\"parent_topic\":"
+		out.RawString(prefix)
+		if in.ParentMessage == nil {
+			out.RawString("null")
+		} else {
+			(*in.ParentMessage).MarshalEasyJSON(out)
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserState) MarshalJSON() ([]byte, error) {
+func (v eventPayloadJSON) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserState) MarshalEasyJSON(w *jwriter.Writer) {
+func (v eventPayloadJSON) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserState) UnmarshalJSON(data []byte) error {
+func (v *eventPayloadJSON) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserState) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *eventPayloadJSON) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang2(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(in *jlexer.Lexer, out *User) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(in *jlexer.Lexer, out *eventJSON) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -317,8 +498,12 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(in *jlexer.Lexer, out *Us
 			continue
 		}
 		switch key {
-		case "userId":
-			out.ID = string(in.String())
+		case "eventId":
+			out.EventID = int(in.Int())
+		case "type":
+			out.Type = EventType(in.String())
+		case "payload":
+			(out.Payload).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -329,42 +514,52 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(in *jlexer.Lexer, out *Us
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang3(out *jwriter.Writer, in User) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang3(out *jwriter.Writer, in eventJSON) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"userId\":"
+		const prefix string = ",\"eventId\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.ID))
+		out.Int(int(in.EventID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"payload\":"
+		out.RawString(prefix)
+		(in.Payload).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v User) MarshalJSON() ([]byte, error) {
+func (v eventJSON) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+func (v eventJSON) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *User) UnmarshalJSON(data []byte) error {
+func (v *eventJSON) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *eventJSON) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang3(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(in *jlexer.Lexer, out *ThreadSubscribers) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(in *jlexer.Lexer, out *UsersListResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -383,27 +578,25 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(in *jlexer.Lexer, out *Th
 			continue
 		}
 		switch key {
-		case "cursor":
-			out.Cursor = string(in.String())
-		case "subscribers":
+		case "users":
 			if in.IsNull() {
 				in.Skip()
-				out.Subscribers = nil
+				out.List = nil
 			} else {
 				in.Delim('[')
-				if out.Subscribers == nil {
+				if out.List == nil {
 					if !in.IsDelim(']') {
-						out.Subscribers = make([]Subscriber, 0, 2)
+						out.List = make([]User, 0, 4)
 					} else {
-						out.Subscribers = []Subscriber{}
+						out.List = []User{}
 					}
 				} else {
-					out.Subscribers = (out.Subscribers)[:0]
+					out.List = (out.List)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v7 Subscriber
-					(v7).UnmarshalEasyJSON(in)
-					out.Subscribers = append(out.Subscribers, v7)
+					var v13 User
+					(v13).UnmarshalEasyJSON(in)
+					out.List = append(out.List, v13)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -418,27 +611,22 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(in *jlexer.Lexer, out *Th
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang4(out *jwriter.Writer, in ThreadSubscribers) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang4(out *jwriter.Writer, in UsersListResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"cursor\":"
+		const prefix string = ",\"users\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Cursor))
-	}
-	{
-		const prefix string = ",\"subscribers\":"
-		out.RawString(prefix)
-		if in.Subscribers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.List == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v8, v9 := range in.Subscribers {
-				if v8 > 0 {
+			for v14, v15 := range in.List {
+				if v14 > 0 {
 					out.RawByte(',')
 				}
-				(v9).MarshalEasyJSON(out)
+				(v15).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -447,29 +635,29 @@ func easyjson6601e8cdEncodeGithubComMailRuImBotGolang4(out *jwriter.Writer, in T
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ThreadSubscribers) MarshalJSON() ([]byte, error) {
+func (v UsersListResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ThreadSubscribers) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UsersListResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ThreadSubscribers) UnmarshalJSON(data []byte) error {
+func (v *UsersListResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ThreadSubscribers) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UsersListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang4(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(in *jlexer.Lexer, out *Thread) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(in *jlexer.Lexer, out *UserState) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -488,8 +676,8 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(in *jlexer.Lexer, out *Th
 			continue
 		}
 		switch key {
-		case "threadId":
-			out.ThreadID = string(in.String())
+		case "lastseen":
+			out.Lastseen = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -500,42 +688,42 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(in *jlexer.Lexer, out *Th
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang5(out *jwriter.Writer, in Thread) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang5(out *jwriter.Writer, in UserState) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"threadId\":"
+		const prefix string = ",\"lastseen\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.ThreadID))
+		out.Int(int(in.Lastseen))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Thread) MarshalJSON() ([]byte, error) {
+func (v UserState) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserState) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Thread) UnmarshalJSON(data []byte) error {
+func (v *UserState) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserState) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang5(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(in *jlexer.Lexer, out *Subscriber) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(in *jlexer.Lexer, out *User) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -554,10 +742,8 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(in *jlexer.Lexer, out *Su
 			continue
 		}
 		switch key {
-		case "sn":
-			out.SN = string(in.String())
-		case "userState":
-			(out.UserState).UnmarshalEasyJSON(in)
+		case "userId":
+			out.ID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -568,47 +754,42 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(in *jlexer.Lexer, out *Su
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang6(out *jwriter.Writer, in Subscriber) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang6(out *jwriter.Writer, in User) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"sn\":"
+		const prefix string = ",\"userId\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.SN))
-	}
-	{
-		const prefix string = ",\"userState\":"
-		out.RawString(prefix)
-		(in.UserState).MarshalEasyJSON(out)
+		out.String(string(in.ID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Subscriber) MarshalJSON() ([]byte, error) {
+func (v User) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Subscriber) MarshalEasyJSON(w *jwriter.Writer) {
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Subscriber) UnmarshalJSON(data []byte) error {
+func (v *User) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Subscriber) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang6(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(in *jlexer.Lexer, out *Response) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(in *jlexer.Lexer, out *ThreadSubscribers) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -627,10 +808,31 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(in *jlexer.Lexer, out *Re
 			continue
 		}
 		switch key {
-		case "ok":
-			out.OK = bool(in.Bool())
-		case "description":
-			out.Description = string(in.String())
+		case "cursor":
+			out.Cursor = string(in.String())
+		case "subscribers":
+			if in.IsNull() {
+				in.Skip()
+				out.Subscribers = nil
+			} else {
+				in.Delim('[')
+				if out.Subscribers == nil {
+					if !in.IsDelim(']') {
+						out.Subscribers = make([]Subscriber, 0, 2)
+					} else {
+						out.Subscribers = []Subscriber{}
+					}
+				} else {
+					out.Subscribers = (out.Subscribers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 Subscriber
+					(v16).UnmarshalEasyJSON(in)
+					out.Subscribers = append(out.Subscribers, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -641,47 +843,58 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(in *jlexer.Lexer, out *Re
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang7(out *jwriter.Writer, in Response) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang7(out *jwriter.Writer, in ThreadSubscribers) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"ok\":"
+		const prefix string = ",\"cursor\":"
 		out.RawString(prefix[1:])
-		out.Bool(bool(in.OK))
+		out.String(string(in.Cursor))
 	}
-	if in.Description != "" {
-		const prefix string = ",\"description\":"
+	{
+		const prefix string = ",\"subscribers\":"
 		out.RawString(prefix)
-		out.String(string(in.Description))
+		if in.Subscribers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Subscribers {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				(v18).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Response) MarshalJSON() ([]byte, error) {
+func (v ThreadSubscribers) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
+func (v ThreadSubscribers) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Response) UnmarshalJSON(data []byte) error {
+func (v *ThreadSubscribers) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *ThreadSubscribers) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang7(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(in *jlexer.Lexer, out *Photo) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(in *jlexer.Lexer, out *Thread) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -700,8 +913,8 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(in *jlexer.Lexer, out *Ph
 			continue
 		}
 		switch key {
-		case "url":
-			out.URL = string(in.String())
+		case "threadId":
+			out.ThreadID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -712,42 +925,42 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(in *jlexer.Lexer, out *Ph
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang8(out *jwriter.Writer, in Photo) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang8(out *jwriter.Writer, in Thread) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"url\":"
+		const prefix string = ",\"threadId\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.URL))
+		out.String(string(in.ThreadID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Photo) MarshalJSON() ([]byte, error) {
+func (v Thread) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Photo) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Photo) UnmarshalJSON(data []byte) error {
+func (v *Thread) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Photo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang8(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(in *jlexer.Lexer, out *PartPayload) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(in *jlexer.Lexer, out *Subscriber) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -766,20 +979,10 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(in *jlexer.Lexer, out *Pa
 			continue
 		}
 		switch key {
-		case "firstName":
-			out.FirstName = string(in.String())
-		case "lastName":
-			out.LastName = string(in.String())
-		case "userId":
-			out.UserID = string(in.String())
-		case "fileId":
-			out.FileID = string(in.String())
-		case "caption":
-			out.Caption = string(in.String())
-		case "type":
-			out.Type = string(in.String())
-		case "message":
-			(out.PartMessage).UnmarshalEasyJSON(in)
+		case "sn":
+			out.SN = string(in.String())
+		case "userState":
+			(out.UserState).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -790,72 +993,47 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(in *jlexer.Lexer, out *Pa
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang9(out *jwriter.Writer, in PartPayload) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang9(out *jwriter.Writer, in Subscriber) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"firstName\":"
+		const prefix string = ",\"sn\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.FirstName))
-	}
-	{
-		const prefix string = ",\"lastName\":"
-		out.RawString(prefix)
-		out.String(string(in.LastName))
-	}
-	{
-		const prefix string = ",\"userId\":"
-		out.RawString(prefix)
-		out.String(string(in.UserID))
-	}
-	{
-		const prefix string = ",\"fileId\":"
-		out.RawString(prefix)
-		out.String(string(in.FileID))
-	}
-	{
-		const prefix string = ",\"caption\":"
-		out.RawString(prefix)
-		out.String(string(in.Caption))
-	}
-	{
-		const prefix string = ",\"type\":"
-		out.RawString(prefix)
-		out.String(string(in.Type))
+		out.String(string(in.SN))
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"userState\":"
 		out.RawString(prefix)
-		(in.PartMessage).MarshalEasyJSON(out)
+		(in.UserState).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PartPayload) MarshalJSON() ([]byte, error) {
+func (v Subscriber) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang9(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PartPayload) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Subscriber) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang9(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PartPayload) UnmarshalJSON(data []byte) error {
+func (v *Subscriber) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PartPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Subscriber) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang9(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(in *jlexer.Lexer, out *PartMessage) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(in *jlexer.Lexer, out *Response) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -874,14 +1052,10 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(in *jlexer.Lexer, out *P
 			continue
 		}
 		switch key {
-		case "from":
-			(out.From).UnmarshalEasyJSON(in)
-		case "msgId":
-			out.MsgID = string(in.String())
-		case "text":
-			out.Text = string(in.String())
-		case "timestamp":
-			out.Timestamp = int(in.Int())
+		case "ok":
+			out.OK = bool(in.Bool())
+		case "description":
+			out.Description = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -892,57 +1066,47 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(in *jlexer.Lexer, out *P
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang10(out *jwriter.Writer, in PartMessage) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang10(out *jwriter.Writer, in Response) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"from\":"
+		const prefix string = ",\"ok\":"
 		out.RawString(prefix[1:])
-		(in.From).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"msgId\":"
-		out.RawString(prefix)
-		out.String(string(in.MsgID))
-	}
-	{
-		const prefix string = ",\"text\":"
-		out.RawString(prefix)
-		out.String(string(in.Text))
+		out.Bool(bool(in.OK))
 	}
-	{
-		const prefix string = ",\"timestamp\":"
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
 		out.RawString(prefix)
-		out.Int(int(in.Timestamp))
+		out.String(string(in.Description))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PartMessage) MarshalJSON() ([]byte, error) {
+func (v Response) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang10(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PartMessage) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang10(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PartMessage) UnmarshalJSON(data []byte) error {
+func (v *Response) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PartMessage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang10(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(in *jlexer.Lexer, out *Part) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(in *jlexer.Lexer, out *Photo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -961,10 +1125,8 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(in *jlexer.Lexer, out *P
 			continue
 		}
 		switch key {
-		case "type":
-			out.Type = PartType(in.String())
-		case "payload":
-			(out.Payload).UnmarshalEasyJSON(in)
+		case "url":
+			out.URL = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -975,47 +1137,42 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(in *jlexer.Lexer, out *P
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang11(out *jwriter.Writer, in Part) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang11(out *jwriter.Writer, in Photo) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"type\":"
+		const prefix string = ",\"url\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Type))
-	}
-	{
-		const prefix string = ",\"payload\":"
-		out.RawString(prefix)
-		(in.Payload).MarshalEasyJSON(out)
+		out.String(string(in.URL))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Part) MarshalJSON() ([]byte, error) {
+func (v Photo) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang11(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Part) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Photo) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang11(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Part) UnmarshalJSON(data []byte) error {
+func (v *Photo) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Photo) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang11(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(in *jlexer.Lexer, out *MembersListResponse) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(in *jlexer.Lexer, out *PartPayload) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1034,29 +1191,20 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(in *jlexer.Lexer, out *M
 			continue
 		}
 		switch key {
-		case "members":
-			if in.IsNull() {
-				in.Skip()
-				out.List = nil
-			} else {
-				in.Delim('[')
-				if out.List == nil {
-					if !in.IsDelim(']') {
-						out.List = make([]ChatMember, 0, 2)
-					} else {
-						out.List = []ChatMember{}
-					}
-				} else {
-					out.List = (out.List)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v10 ChatMember
-					(v10).UnmarshalEasyJSON(in)
-					out.List = append(out.List, v10)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
+		case "firstName":
+			out.FirstName = string(in.String())
+		case "lastName":
+			out.LastName = string(in.String())
+		case "userId":
+			out.UserID = string(in.String())
+		case "fileId":
+			out.FileID = string(in.String())
+		case "caption":
+			out.Caption = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "message":
+			(out.PartMessage).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -1067,53 +1215,72 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(in *jlexer.Lexer, out *M
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang12(out *jwriter.Writer, in MembersListResponse) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang12(out *jwriter.Writer, in PartPayload) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"members\":"
+		const prefix string = ",\"firstName\":"
 		out.RawString(prefix[1:])
-		if in.List == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v11, v12 := range in.List {
-				if v11 > 0 {
-					out.RawByte(',')
-				}
-				(v12).MarshalEasyJSON(out)
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.FirstName))
+	}
+	{
+		const prefix string = ",\"lastName\":"
+		out.RawString(prefix)
+		out.String(string(in.LastName))
+	}
+	{
+		const prefix string = ",\"userId\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"fileId\":"
+		out.RawString(prefix)
+		out.String(string(in.FileID))
+	}
+	{
+		const prefix string = ",\"caption\":"
+		out.RawString(prefix)
+		out.String(string(in.Caption))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		(in.PartMessage).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v MembersListResponse) MarshalJSON() ([]byte, error) {
+func (v PartPayload) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang12(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v MembersListResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PartPayload) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang12(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *MembersListResponse) UnmarshalJSON(data []byte) error {
+func (v *PartPayload) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *MembersListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PartPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang12(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(in *jlexer.Lexer, out *EventPayload) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(in *jlexer.Lexer, out *PartMessage) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1132,6 +1299,16 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(in *jlexer.Lexer, out *E
 			continue
 		}
 		switch key {
+		case "from":
+			(out.From).UnmarshalEasyJSON(in)
+		case "msgId":
+			out.MsgID = string(in.String())
+		case "text":
+			out.Text = string(in.String())
+		case "timestamp":
+			out.Timestamp = int(in.Int())
+		case "chat":
+			(out.Chat).UnmarshalEasyJSON(in)
 		case "parts":
 			if in.IsNull() {
 				in.Skip()
@@ -1148,89 +1325,13 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(in *jlexer.Lexer, out *E
 					out.Parts = (out.Parts)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v13 Part
-					(v13).UnmarshalEasyJSON(in)
-					out.Parts = append(out.Parts, v13)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "queryId":
-			out.QueryID = string(in.String())
-		case "message":
-			(out.CallbackMsg).UnmarshalEasyJSON(in)
-		case "callbackData":
-			out.CallbackData = string(in.String())
-		case "leftMembers":
-			if in.IsNull() {
-				in.Skip()
-				out.LeftMembers = nil
-			} else {
-				in.Delim('[')
-				if out.LeftMembers == nil {
-					if !in.IsDelim(']') {
-						out.LeftMembers = make([]Contact, 0, 1)
-					} else {
-						out.LeftMembers = []Contact{}
-					}
-				} else {
-					out.LeftMembers = (out.LeftMembers)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v14 Contact
-					(v14).UnmarshalEasyJSON(in)
-					out.LeftMembers = append(out.LeftMembers, v14)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "newMembers":
-			if in.IsNull() {
-				in.Skip()
-				out.NewMembers = nil
-			} else {
-				in.Delim('[')
-				if out.NewMembers == nil {
-					if !in.IsDelim(']') {
-						out.NewMembers = make([]Contact, 0, 1)
-					} else {
-						out.NewMembers = []Contact{}
-					}
-				} else {
-					out.NewMembers = (out.NewMembers)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v15 Contact
-					(v15).UnmarshalEasyJSON(in)
-					out.NewMembers = append(out.NewMembers, v15)
+					var v19 Part
+					(v19).UnmarshalEasyJSON(in)
+					out.Parts = append(out.Parts, v19)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "addedBy":
-			(out.AddedBy).UnmarshalEasyJSON(in)
-		case "removedBy":
-			(out.RemovedBy).UnmarshalEasyJSON(in)
-		case "msgId":
-			out.MsgID = string(in.String())
-		case "chat":
-			(out.Chat).UnmarshalEasyJSON(in)
-		case "from":
-			(out.From).UnmarshalEasyJSON(in)
-		case "text":
-			out.Text = string(in.String())
-		case "timestamp":
-			out.Timestamp = int(in.Int())
-		case "parent_topic":
-			if in.IsNull() {
-				in.Skip()
-				out.ParentMessage = nil
-			} else {
-				if out.ParentMessage == nil {
-					out.ParentMessage = new(ParentMessage)
-				}
-				(*out.ParentMessage).UnmarshalEasyJSON(in)
-			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1241,65 +1342,43 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(in *jlexer.Lexer, out *E
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang13(out *jwriter.Writer, in EventPayload) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang13(out *jwriter.Writer, in PartMessage) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"parts\":"
+		const prefix string = ",\"from\":"
 		out.RawString(prefix[1:])
-		if in.Parts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v16, v17 := range in.Parts {
-				if v16 > 0 {
-					out.RawByte(',')
-				}
-				(v17).MarshalEasyJSON(out)
-			}
-			out.RawByte(']')
-		}
+		(in.From).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"queryId\":"
+		const prefix string = ",\"msgId\":"
 		out.RawString(prefix)
-		out.String(string(in.QueryID))
+		out.String(string(in.MsgID))
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"text\":"
 		out.RawString(prefix)
-		(in.CallbackMsg).MarshalEasyJSON(out)
+		out.String(string(in.Text))
 	}
 	{
-		const prefix string = ",\"callbackData\":"
+		const prefix string = ",\"timestamp\":"
 		out.RawString(prefix)
-		out.String(string(in.CallbackData))
+		out.Int(int(in.Timestamp))
 	}
 	{
-		const prefix string = ",\"leftMembers\":"
+		const prefix string = ",\"chat\":"
 		out.RawString(prefix)
-		if in.LeftMembers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v18, v19 := range in.LeftMembers {
-				if v18 > 0 {
-					out.RawByte(',')
-				}
-				(v19).MarshalEasyJSON(out)
-			}
-			out.RawByte(']')
-		}
+		(in.Chat).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"newMembers\":"
+		const prefix string = ",\"parts\":"
 		out.RawString(prefix)
-		if in.NewMembers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.Parts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v20, v21 := range in.NewMembers {
+			for v20, v21 := range in.Parts {
 				if v20 > 0 {
 					out.RawByte(',')
 				}
@@ -1308,77 +1387,33 @@ func easyjson6601e8cdEncodeGithubComMailRuImBotGolang13(out *jwriter.Writer, in
 			out.RawByte(']')
 		}
 	}
-	{
-		const prefix string = ",\"addedBy\":"
-		out.RawString(prefix)
-		(in.AddedBy).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"removedBy\":"
-		out.RawString(prefix)
-		(in.RemovedBy).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"msgId\":"
-		out.RawString(prefix)
-		out.String(string(in.MsgID))
-	}
-	{
-		const prefix string = ",\"chat\":"
-		out.RawString(prefix)
-		(in.Chat).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"from\":"
-		out.RawString(prefix)
-		(in.From).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"text\":"
-		out.RawString(prefix)
-		out.String(string(in.Text))
-	}
-	{
-		const prefix string = ",\"timestamp\":"
-		out.RawString(prefix)
-		out.Int(int(in.Timestamp))
-	}
-	{
-		const prefix string = ",\"parent_topic\":"
-		out.RawString(prefix)
-		if in.ParentMessage == nil {
-			out.RawString("null")
-		} else {
-			(*in.ParentMessage).MarshalEasyJSON(out)
-		}
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v EventPayload) MarshalJSON() ([]byte, error) {
+func (v PartMessage) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang13(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v EventPayload) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PartMessage) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang13(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *EventPayload) UnmarshalJSON(data []byte) error {
+func (v *PartMessage) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *EventPayload) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PartMessage) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang13(l, v)
 }
-func easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(in *jlexer.Lexer, out *Event) {
+func easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(in *jlexer.Lexer, out *MembersListResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1397,12 +1432,29 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(in *jlexer.Lexer, out *E
 			continue
 		}
 		switch key {
-		case "eventId":
-			out.EventID = int(in.Int())
-		case "type":
-			out.Type = EventType(in.String())
-		case "payload":
-			(out.Payload).UnmarshalEasyJSON(in)
+		case "members":
+			if in.IsNull() {
+				in.Skip()
+				out.List = nil
+			} else {
+				in.Delim('[')
+				if out.List == nil {
+					if !in.IsDelim(']') {
+						out.List = make([]ChatMember, 0, 2)
+					} else {
+						out.List = []ChatMember{}
+					}
+				} else {
+					out.List = (out.List)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 ChatMember
+					(v22).UnmarshalEasyJSON(in)
+					out.List = append(out.List, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1413,49 +1465,50 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(in *jlexer.Lexer, out *E
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComMailRuImBotGolang14(out *jwriter.Writer, in Event) {
+func easyjson6601e8cdEncodeGithubComMailRuImBotGolang14(out *jwriter.Writer, in MembersListResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"eventId\":"
+		const prefix string = ",\"members\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.EventID))
-	}
-	{
-		const prefix string = ",\"type\":"
-		out.RawString(prefix)
-		out.String(string(in.Type))
-	}
-	{
-		const prefix string = ",\"payload\":"
-		out.RawString(prefix)
-		(in.Payload).MarshalEasyJSON(out)
+		if in.List == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.List {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				(v24).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Event) MarshalJSON() ([]byte, error) {
+func (v MembersListResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang14(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Event) MarshalEasyJSON(w *jwriter.Writer) {
+func (v MembersListResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson6601e8cdEncodeGithubComMailRuImBotGolang14(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Event) UnmarshalJSON(data []byte) error {
+func (v *MembersListResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Event) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *MembersListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6601e8cdDecodeGithubComMailRuImBotGolang14(l, v)
 }
 func easyjson6601e8cdDecodeGithubComMailRuImBotGolang15(in *jlexer.Lexer, out *Contact) {
@@ -1659,9 +1712,9 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang17(in *jlexer.Lexer, out *B
 					out.Photo = (out.Photo)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v22 Photo
-					(v22).UnmarshalEasyJSON(in)
-					out.Photo = append(out.Photo, v22)
+					var v25 Photo
+					(v25).UnmarshalEasyJSON(in)
+					out.Photo = append(out.Photo, v25)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1704,11 +1757,11 @@ func easyjson6601e8cdEncodeGithubComMailRuImBotGolang17(out *jwriter.Writer, in
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v23, v24 := range in.Photo {
-				if v23 > 0 {
+			for v26, v27 := range in.Photo {
+				if v26 > 0 {
 					out.RawByte(',')
 				}
-				(v24).MarshalEasyJSON(out)
+				(v27).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -1892,9 +1945,9 @@ func easyjson6601e8cdDecodeGithubComMailRuImBotGolang19(in *jlexer.Lexer, out *A
 					out.List = (out.List)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v25 ChatMember
-					(v25).UnmarshalEasyJSON(in)
-					out.List = append(out.List, v25)
+					var v28 ChatMember
+					(v28).UnmarshalEasyJSON(in)
+					out.List = append(out.List, v28)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1920,11 +1973,11 @@ func easyjson6601e8cdEncodeGithubComMailRuImBotGolang19(out *jwriter.Writer, in
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v26, v27 := range in.List {
-				if v26 > 0 {
+			for v29, v30 := range in.List {
+				if v29 > 0 {
 					out.RawByte(',')
 				}
-				(v27).MarshalEasyJSON(out)
+				(v30).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2,26 +2,62 @@ package botgolang
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	dura "github.com/hako/durafmt"
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	sleepTime = time.Second * 3
-)
-
-var (
-	sleepTimeStr = dura.Parse(sleepTime)
-)
-
 type Updater struct {
-	logger      *logrus.Logger
-	client      *Client
+	logger   Logger
+	client   *Client
+	PollTime int
+
+	// mu guards lastEventID and the state of the lifecycle and status
+	mu          sync.Mutex
 	lastEventID int
-	PollTime    int
+
+	// buffer size of the channel created by Start
+	bufferSize int
+
+	// cancellation of the run started by Start, request to stop it and its completion
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+
+	// closed by Resume, nil if the updater is not paused
+	resumed chan struct{}
+
+	// status of the updater, runs is the number of running loops
+	runs          int
+	lastPollTime  time.Time
+	lastEventTime time.Time
+	lastError     error
+	lastErrorTime time.Time
+	eventRate     rateCounter
+
+	metrics *Metrics
+
+	// store of lastEventID, it is kept only in memory if nil
+	offsets OffsetStore
+
+	// tracker of unacked events in at-least-once delivery mode, nil otherwise
+	acks *ackTracker
+
+	// called for events which were not acked in all delivery attempts
+	onGiveUp func(Event)
+
+	// handling of failed polls, the number of failed polls in a row and the time of the first one
+	poll        PollPolicy
+	failures    int
+	outageStart time.Time
+
+	// hook for events and parts of unknown types, they are logged once per type if it is nil
+	onUnknownType func(UnknownType)
+	unknownLogged map[string]bool
 }
 
 // NewMessageFromPart returns new message based on part message
@@ -35,64 +71,292 @@ func (u *Updater) NewMessageFromPayload(message EventPayload) *Message {
 	}
 }
 
+// RunUpdatesCheck polls events and delivers them to ch until ctx is done, then closes ch.
+// Use Start and Stop to run the updater in the background and stop it gracefully.
 func (u *Updater) RunUpdatesCheck(ctx context.Context, ch chan<- Event) {
-	_, err := u.GetLastEventsWithContext(ctx, 0)
-	if err != nil {
-		u.logger.WithFields(logrus.Fields{
-			"err": err,
-		}).Debug("cannot make initial request to events")
+	u.run(ctx, ch, nil)
+}
+
+// run is the loop of polls, it stops between polls when stop is closed
+func (u *Updater) run(ctx context.Context, ch chan<- Event, stop <-chan struct{}) {
+	defer close(ch)
+
+	u.mu.Lock()
+	u.runs++
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		u.runs--
+		u.mu.Unlock()
+	}()
+
+	if u.acks != nil {
+		u.rewindUnacked()
+	}
+	u.loadOffset(ctx)
+
+	if u.acks != nil {
+		var wg sync.WaitGroup
+		redeliverCtx, cancel := context.WithCancel(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.redeliver(redeliverCtx, ch)
+		}()
+		// the channel is closed only after redelivery stops
+		defer wg.Wait()
+		defer cancel()
 	}
 
 	for {
+		if !u.waitResumed(ctx, stop) {
+			return
+		}
+
 		select {
 		case <-ctx.Done():
-			close(ch)
+			return
+		case <-stop:
 			return
 		default:
 			events, err := u.GetLastEventsWithContext(ctx, u.PollTime)
 			if err != nil {
-				u.logger.WithFields(logrus.Fields{
+				if ctx.Err() != nil {
+					return
+				}
+
+				delay, failures := u.pollFailed(err)
+				delayStr := dura.Parse(delay.Round(time.Millisecond))
+				u.logger.Error(fmt.Sprintf("Failed to get updates, retrying in %s ...", delayStr), LogFields{
 					"err":            err,
-					"retry interval": sleepTimeStr,
-				}).Errorf("Failed to get updates, retrying in %s ...", sleepTimeStr)
-				time.Sleep(sleepTime)
+					"retry interval": delayStr,
+					"failures":       failures,
+				})
+
+				if !sleepUntilStop(ctx, stop, delay) {
+					return
+				}
 
 				continue
 			}
+			u.pollSucceeded()
 
-			for _, event := range events {
-				event.client = u.client
-				event.Payload.client = u.client
-
-				ch <- *event
+			if !u.deliver(ctx, ch, events) {
+				return
 			}
 		}
 	}
 }
 
+// send delivers the event to the channel, it returns false if ctx is done before that
+func (u *Updater) send(ctx context.Context, ch chan<- Event, event *Event) bool {
+	u.traceEvent(ctx, event)
+
+	if u.logger.DebugEnabled() {
+		u.logger.Debug("delivering event", LogFields{
+			"eventId": event.EventID,
+			"type":    event.Type,
+			"chatId":  u.client.redact.redact(u.client.redact.UserIDs, event.chat().ID),
+			"attempt": event.DeliveryAttempt(),
+		})
+	}
+
+	select {
+	case ch <- *event:
+		now := time.Now()
+		u.mu.Lock()
+		u.lastEventTime = now
+		u.eventRate.add(now, 1)
+		u.mu.Unlock()
+		return true
+	case <-ctx.Done():
+		event.Done(ctx.Err())
+		return false
+	}
+}
+
+// rewindUnacked forgets events delivered by the previous run and makes the updater
+// fetch them again from the first unacked one, so they are not tracked twice
+func (u *Updater) rewindUnacked() {
+	first := u.acks.reset()
+	if first == 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if first-1 < u.lastEventID {
+		u.lastEventID = first - 1
+	}
+}
+
+// loadOffset restores lastEventID from the offset store
+func (u *Updater) loadOffset(ctx context.Context) {
+	if u.offsets == nil {
+		return
+	}
+
+	eventID, err := u.offsets.Load(ctx)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err != nil {
+		u.logger.Error("cannot load offset, starting from the last known event", LogFields{
+			"err":         err,
+			"lastEventId": u.lastEventID,
+		})
+		return
+	}
+
+	u.lastEventID = eventID
+}
+
+// deliver sends the polled events to the channel. Without acks the offset is saved
+// once per batch, up to the last event delivered before the updater stopped.
+func (u *Updater) deliver(ctx context.Context, ch chan<- Event, events []*Event) bool {
+	delivered := 0
+	defer func() {
+		if u.acks == nil && delivered != 0 {
+			u.saveOffset(ctx, delivered)
+		}
+	}()
+
+	for _, event := range events {
+		event.bind(u.client)
+
+		if u.acks != nil {
+			eventID := event.EventID
+			event.ack = func() { u.ackEvent(eventID) }
+			event.attempt = u.acks.add(*event, time.Now())
+		}
+
+		if !u.send(ctx, ch, event) {
+			return false
+		}
+		delivered = event.EventID
+	}
+
+	return true
+}
+
+// saveOffset stores the id of the delivered event
+func (u *Updater) saveOffset(ctx context.Context, eventID int) {
+	if u.offsets == nil {
+		return
+	}
+
+	if err := u.offsets.Save(ctx, eventID); err != nil {
+		u.logger.Error("cannot save offset", LogFields{
+			"err":     err,
+			"eventId": eventID,
+		})
+	}
+}
+
+// traceEvent opens the root span of the event
+func (u *Updater) traceEvent(ctx context.Context, event *Event) {
+	tracer := u.client.tracer
+	if tracer == nil {
+		tracer = NoopTracer()
+	}
+
+	// the event is processed after the updater stops, e.g. when it is drained from the buffer
+	ctx = ContextWithRequestID(context.WithoutCancel(ctx), eventRequestID(event.EventID))
+	event.ctx, event.span = tracer.Start(ctx, "event "+string(event.Type), SpanAttributes{
+		"event_id":   event.EventID,
+		"event_type": string(event.Type),
+		"attempt":    event.DeliveryAttempt(),
+		"chat_id":    u.client.redact.redact(u.client.redact.UserIDs, event.chat().ID),
+	})
+}
+
 func (u *Updater) GetLastEvents(pollTime int) ([]*Event, error) {
 	return u.GetLastEventsWithContext(context.Background(), pollTime)
 }
 
+// GetLastEventsWithContext polls events after the last received one.
+// The request is limited by the poll time plus PollPolicy.StallTimeout,
+// the connection is dropped and ErrPollStalled is returned if the API doesn't respond in time.
 func (u *Updater) GetLastEventsWithContext(ctx context.Context, pollTime int) ([]*Event, error) {
-	events, err := u.client.GetEventsWithContext(ctx, u.lastEventID, pollTime)
+	pollCtx, cancel := context.WithTimeout(ctx, time.Duration(pollTime)*time.Second+u.poll.StallTimeout)
+	defer cancel()
+
+	u.mu.Lock()
+	lastEventID := u.lastEventID
+	u.mu.Unlock()
+
+	events, err := u.client.GetEventsWithContext(pollCtx, lastEventID, pollTime)
+	if err != nil && ctx.Err() == nil && errors.Is(pollCtx.Err(), context.DeadlineExceeded) {
+		// the connection may be broken without being closed, so it must not be reused
+		u.client.client.CloseIdleConnections()
+		err = fmt.Errorf("%w: %w", ErrPollStalled, err)
+	}
 	if err != nil {
-		u.logger.WithFields(logrus.Fields{
+		u.logger.Debug("events getting error", LogFields{
 			"err":    err,
 			"events": events,
-		}).Debug("events getting error")
-		return events, fmt.Errorf("cannot get events: %s", err)
+		})
+		return events, fmt.Errorf("cannot get events: %w", err)
 	}
 
-	count := len(events)
-	if count > 0 {
+	u.mu.Lock()
+	// concurrent polls may finish in any order, the id must not go back
+	if count := len(events); count > 0 && events[count-1].EventID > u.lastEventID {
 		u.lastEventID = events[count-1].EventID
 	}
+	lastEventID = u.lastEventID
+	u.lastPollTime = time.Now()
+	u.mu.Unlock()
+
+	if u.metrics != nil {
+		u.metrics.observePoll(events, lastEventID)
+	}
+	for _, event := range events {
+		for _, unknown := range unknownTypes(event) {
+			u.reportUnknownType(unknown)
+		}
+	}
 
 	return events, nil
 }
 
+// reportUnknownType passes the unknown type to the hook and counts it
+func (u *Updater) reportUnknownType(unknown UnknownType) {
+	if u.metrics != nil {
+		u.metrics.observeUnknownType(unknown.Kind, unknown.Type)
+	}
+
+	if u.onUnknownType != nil {
+		u.onUnknownType(unknown)
+		return
+	}
+
+	key := unknown.Kind + " " + unknown.Type
+	u.mu.Lock()
+	logged := u.unknownLogged[key]
+	if !logged {
+		if u.unknownLogged == nil {
+			u.unknownLogged = map[string]bool{}
+		}
+		u.unknownLogged[key] = true
+	}
+	u.mu.Unlock()
+
+	if !logged {
+		u.logger.Warn("received "+unknown.Kind+" of unknown type", LogFields{
+			"type":    unknown.Type,
+			"eventId": unknown.EventID,
+		})
+	}
+}
+
 func NewUpdater(client *Client, pollTime int, logger *logrus.Logger) *Updater {
+	return newUpdater(client, pollTime, NewLogrusLogger(logger))
+}
+
+func newUpdater(client *Client, pollTime int, logger Logger) *Updater {
 	if pollTime == 0 {
 		pollTime = 60
 	}
@@ -102,5 +366,6 @@ func NewUpdater(client *Client, pollTime int, logger *logrus.Logger) *Updater {
 		lastEventID: 0,
 		PollTime:    pollTime,
 		logger:      logger,
+		poll:        DefaultPollPolicy(),
 	}
 }
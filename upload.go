@@ -0,0 +1,124 @@
+package botgolang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+)
+
+// errUploadDone stops writing of the body which is no longer read by the request
+var errUploadDone = errors.New("upload is done")
+
+// fileUpload is a file sent in multipart body of the request
+type fileUpload struct {
+	name   string
+	reader io.Reader
+
+	// size of the data in reader, -1 if unknown
+	size int64
+}
+
+func newFileUpload(name string, reader io.Reader, size int64) *fileUpload {
+	if size <= 0 {
+		size = readerSize(reader)
+	}
+
+	return &fileUpload{
+		name:   name,
+		reader: reader,
+		size:   size,
+	}
+}
+
+func osFileUpload(file *os.File) *fileUpload {
+	if file == nil {
+		return nil
+	}
+
+	return newFileUpload(file.Name(), file, 0)
+}
+
+// readerSize returns the number of bytes left in the reader or -1 if it cannot be determined
+func readerSize(reader io.Reader) int64 {
+	switch r := reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+
+	return -1
+}
+
+// body streams multipart form with the file through a pipe, so the file is never buffered in memory.
+// It returns the body, its content type and length (-1 if the size of the file is unknown).
+// wait closes the body and returns after the goroutine writing it stops reading the file,
+// it must be called before the file is read again, e.g. rewound for a retry.
+func (u *fileUpload) body() (body io.ReadCloser, contentType string, length int64, wait func(), err error) {
+	pipeReader, pipeWriter := io.Pipe()
+	multipartWriter := multipart.NewWriter(pipeWriter)
+
+	length = -1
+	if u.size >= 0 {
+		overhead, err := u.overhead(multipartWriter.Boundary())
+		if err != nil {
+			return nil, "", 0, nil, err
+		}
+		length = overhead + u.size
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		fileWriter, err := multipartWriter.CreateFormFile("file", u.name)
+		if err != nil {
+			pipeWriter.CloseWithError(fmt.Errorf("cannot create multipart writer: %w", err))
+			return
+		}
+
+		if _, err := io.Copy(fileWriter, u.reader); err != nil {
+			pipeWriter.CloseWithError(fmt.Errorf("cannot copy file into request: %w", err))
+			return
+		}
+
+		pipeWriter.CloseWithError(multipartWriter.Close())
+	}()
+
+	wait = func() {
+		pipeReader.CloseWithError(errUploadDone)
+		<-done
+	}
+
+	return pipeReader, multipartWriter.FormDataContentType(), length, wait, nil
+}
+
+// overhead returns the length of multipart headers and trailer around the file
+func (u *fileUpload) overhead(boundary string) (int64, error) {
+	buffer := &bytes.Buffer{}
+	multipartWriter := multipart.NewWriter(buffer)
+	if err := multipartWriter.SetBoundary(boundary); err != nil {
+		return 0, fmt.Errorf("cannot set multipart boundary: %w", err)
+	}
+
+	if _, err := multipartWriter.CreateFormFile("file", u.name); err != nil {
+		return 0, fmt.Errorf("cannot create multipart writer: %w", err)
+	}
+
+	if err := multipartWriter.Close(); err != nil {
+		return 0, fmt.Errorf("cannot close multipartWriter: %w", err)
+	}
+
+	return int64(buffer.Len()), nil
+}